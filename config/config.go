@@ -37,8 +37,23 @@ type ServerConfig struct {
 	// Maximum request size
 	MaxRequestSize int64 `json:"max_request_size" yaml:"max_request_size"`
 
+	// Maximum concurrent HTTP connections; further connections wait to be accepted (0 = unlimited)
+	MaxConnections int `json:"max_connections" yaml:"max_connections"`
+
 	// Security headers configuration
 	Security SecurityConfig `json:"security" yaml:"security"`
+
+	// Admin endpoints configuration
+	Admin AdminConfig `json:"admin" yaml:"admin"`
+}
+
+// AdminConfig contains settings for the admin endpoints
+type AdminConfig struct {
+	// Port of the separate admin listener; admin endpoints are not served when 0
+	Port int `json:"port" yaml:"port"`
+
+	// Bearer token required on every admin request
+	Token string `json:"token" yaml:"token"`
 }
 
 // SecurityConfig contains security-related settings
@@ -90,11 +105,45 @@ type GRPCConfig struct {
 	// Message size limits
 	MaxMessageSize int `json:"max_message_size" yaml:"max_message_size"`
 
+	// Default compressor for calls to the gRPC server (e.g. "gzip"; empty disables compression)
+	Compression string `json:"compression" yaml:"compression"`
+
 	// Header forwarding configuration
 	HeaderForwarding HeaderForwardingConfig `json:"header_forwarding" yaml:"header_forwarding"`
 
 	// FileDescriptorSet configuration
 	DescriptorSet DescriptorSetConfig `json:"descriptor_set" yaml:"descriptor_set"`
+
+	// Answer codes.Unauthenticated failures with a credential refresh error instead of a generic tool error
+	SignalUnauthenticated bool `json:"signal_unauthenticated" yaml:"signal_unauthenticated"`
+
+	// Fail discovery when the server exposes no services instead of serving an empty tool list
+	StrictDiscovery bool `json:"strict_discovery" yaml:"strict_discovery"`
+
+	// Invoke methods on a different target than the discovery connection, keyed by
+	// full service name or "*" for all services (e.g. reflection via a proxy, calls direct)
+	InvocationTargets map[string]TargetConfig `json:"invocation_targets" yaml:"invocation_targets"`
+
+	// Server-streaming methods exposed as tools with aggregated results
+	ServerStreaming ServerStreamingConfig `json:"server_streaming" yaml:"server_streaming"`
+}
+
+// ServerStreamingConfig controls how server-streaming methods are exposed and aggregated
+type ServerStreamingConfig struct {
+	// Expose server-streaming methods as tools (client and bidirectional streaming never are)
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Maximum messages aggregated per call before it fails (0 = unlimited)
+	MaxMessages int `json:"max_messages" yaml:"max_messages"`
+
+	// Maximum total JSON bytes aggregated per call before it fails (0 = unlimited)
+	MaxBytes int64 `json:"max_bytes" yaml:"max_bytes"`
+}
+
+// TargetConfig identifies a gRPC server address
+type TargetConfig struct {
+	Host string `json:"host" yaml:"host"`
+	Port int    `json:"port" yaml:"port"`
 }
 
 // KeepAliveConfig contains keep-alive settings
@@ -108,6 +157,9 @@ type KeepAliveConfig struct {
 type ReconnectConfig struct {
 	Interval    time.Duration `json:"interval" yaml:"interval"`
 	MaxAttempts int           `json:"max_attempts" yaml:"max_attempts"`
+
+	// Interval between background health checks that trigger a reconnect (0 = disabled)
+	HealthCheckInterval time.Duration `json:"health_check_interval" yaml:"health_check_interval"`
 }
 
 // HeaderForwardingConfig contains header forwarding settings
@@ -141,6 +193,21 @@ type DescriptorSetConfig struct {
 
 	// Include source location info for comment extraction
 	IncludeSourceInfo bool `json:"include_source_info" yaml:"include_source_info"`
+
+	// Retry reading the file on transient filesystem errors
+	Retry RetryConfig `json:"retry" yaml:"retry"`
+
+	// Convert backslash separators to "/" in file names and dependencies before resolving them
+	NormalizePaths bool `json:"normalize_paths" yaml:"normalize_paths"`
+}
+
+// RetryConfig contains retry settings for transient failures
+type RetryConfig struct {
+	// Total number of attempts; values below 2 disable retries
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+
+	// Delay before the first retry, doubled after each attempt
+	Backoff time.Duration `json:"backoff" yaml:"backoff"`
 }
 
 // MCPConfig contains MCP protocol settings
@@ -150,6 +217,12 @@ type MCPConfig struct {
 
 	// Protocol version
 	ProtocolVersion string `json:"protocol_version" yaml:"protocol_version"`
+
+	// Deployment-specific guidance returned to clients in the initialize result
+	Instructions string `json:"instructions" yaml:"instructions"`
+
+	// Non-standard capabilities advertised under capabilities.experimental
+	Experimental map[string]interface{} `json:"experimental" yaml:"experimental"`
 }
 
 // ValidationConfig contains validation limits
@@ -158,6 +231,9 @@ type ValidationConfig struct {
 	MaxToolNameLength int   `json:"max_tool_name_length" yaml:"max_tool_name_length"`
 	MaxRequestSize    int64 `json:"max_request_size" yaml:"max_request_size"`
 	MaxResponseSize   int64 `json:"max_response_size" yaml:"max_response_size"`
+
+	// Accept "2" or a missing jsonrpc field as "2.0" (strict "2.0" only when false)
+	LenientJSONRPCVersion bool `json:"lenient_jsonrpc_version" yaml:"lenient_jsonrpc_version"`
 }
 
 // SessionConfig contains session management settings
@@ -173,6 +249,10 @@ type SessionConfig struct {
 
 	// Session rate limiting
 	RateLimit SessionRateLimitConfig `json:"rate_limit" yaml:"rate_limit"`
+
+	// Window during which a repeated JSON-RPC id within a session replays the
+	// original response instead of invoking again (0 disables de-duplication)
+	DedupWindow time.Duration `json:"dedup_window" yaml:"dedup_window"`
 }
 
 // SessionRateLimitConfig contains session-specific rate limiting
@@ -191,6 +271,34 @@ type ToolsConfig struct {
 	MaxDepth      int `json:"max_depth" yaml:"max_depth"`
 	MaxFields     int `json:"max_fields" yaml:"max_fields"`
 	MaxEnumValues int `json:"max_enum_values" yaml:"max_enum_values"`
+
+	// When a method uses the same message for input and output, give the input schema an
+	// "$id" and emit the output schema as a "$ref" to it instead of a second copy
+	// (clients must resolve the reference across the tool's two schemas)
+	ShareIdenticalSchemas bool `json:"share_identical_schemas" yaml:"share_identical_schemas"`
+
+	// Expose the built-in __list_services tool returning the service catalog
+	ServiceCatalogTool bool `json:"service_catalog_tool" yaml:"service_catalog_tool"`
+
+	// Emit a "title" with the message's short name on each object schema
+	EmitTitles bool `json:"emit_titles" yaml:"emit_titles"`
+
+	// JSON file mapping tool names to input schemas that replace the generated ones
+	InputSchemaOverridesPath string `json:"input_schema_overrides_path" yaml:"input_schema_overrides_path"`
+
+	// Maximum distinct recursive message definitions ("$defs") emitted per tool, across
+	// its input and output schemas
+	MaxDefinitions int `json:"max_definitions" yaml:"max_definitions"`
+
+	// What to do past MaxDefinitions: "truncate" omits further recursion, "error" skips the tool
+	DefinitionOverflow string `json:"definition_overflow" yaml:"definition_overflow"`
+
+	// Description for methods without comments; {method} and {service} are substituted
+	FallbackDescription string `json:"fallback_description" yaml:"fallback_description"`
+
+	// Emit top-level fields only, with nested messages as "schema://<full name>"
+	// references fetched on demand via resources/read
+	LazySchemas bool `json:"lazy_schemas" yaml:"lazy_schemas"`
 }
 
 // CacheConfig contains caching settings
@@ -205,6 +313,14 @@ type LoggingConfig struct {
 	Level       string `json:"level" yaml:"level"`
 	Format      string `json:"format" yaml:"format"`
 	Development bool   `json:"development" yaml:"development"`
+
+	// Per-subsystem level overrides keyed by logger name (e.g., "discovery": "debug")
+	Levels map[string]string `json:"levels" yaml:"levels"`
+
+	// Argument paths masked in logs, keyed by tool name ("*" applies to all tools),
+	// e.g. "user_service_login": ["credentials.password"]. The request log is the only
+	// place tool arguments are recorded; there is no separate audit log.
+	Redaction map[string][]string `json:"redaction" yaml:"redaction"`
 }
 
 // Default returns a configuration with sensible defaults
@@ -214,6 +330,7 @@ func Default() *Config {
 			Port:           50053,
 			Timeout:        30 * time.Second,
 			MaxRequestSize: 4 * 1024 * 1024, // 4MB
+			MaxConnections: 0,               // Unlimited
 			Security: SecurityConfig{
 				EnableHeaders: true,
 				CORS: CORSConfig{
@@ -227,6 +344,9 @@ func Default() *Config {
 					WindowSize:        time.Minute,
 				},
 			},
+			Admin: AdminConfig{
+				Port: 0, // Admin endpoints disabled
+			},
 		},
 		GRPC: GRPCConfig{
 			Host:           "localhost",
@@ -239,10 +359,16 @@ func Default() *Config {
 				PermitWithoutStream: true,
 			},
 			Reconnect: ReconnectConfig{
-				Interval:    5 * time.Second,
-				MaxAttempts: 5,
+				Interval:            5 * time.Second,
+				MaxAttempts:         5,
+				HealthCheckInterval: 0, // Background health checks disabled
 			},
 			MaxMessageSize: 4 * 1024 * 1024, // 4MB
+			ServerStreaming: ServerStreamingConfig{
+				Enabled:     false, // Streaming methods are skipped unless enabled
+				MaxMessages: 1000,
+				MaxBytes:    4 * 1024 * 1024, // 4MB
+			},
 			HeaderForwarding: HeaderForwardingConfig{
 				Enabled: true,
 				AllowedHeaders: []string{
@@ -272,6 +398,11 @@ func Default() *Config {
 				Path:                 "",
 				PreferOverReflection: false,
 				IncludeSourceInfo:    true,
+				Retry: RetryConfig{
+					MaxAttempts: 1, // No retries by default
+					Backoff:     100 * time.Millisecond,
+				},
+				NormalizePaths: true,
 			},
 		},
 		MCP: MCPConfig{
@@ -281,6 +412,8 @@ func Default() *Config {
 				MaxToolNameLength: 128,
 				MaxRequestSize:    4 * 1024 * 1024,  // 4MB
 				MaxResponseSize:   16 * 1024 * 1024, // 16MB
+
+				LenientJSONRPCVersion: false,
 			},
 		},
 		Session: SessionConfig{
@@ -292,6 +425,7 @@ func Default() *Config {
 				BurstSize:         20,
 				WindowSize:        time.Minute,
 			},
+			DedupWindow: 0, // Disabled by default
 		},
 		Tools: ToolsConfig{
 			Cache: CacheConfig{
@@ -299,9 +433,15 @@ func Default() *Config {
 				TTL:        1 * time.Hour,
 				MaxEntries: 1000,
 			},
-			MaxDepth:      10,
-			MaxFields:     100,
-			MaxEnumValues: 50,
+			MaxDepth:              10,
+			MaxFields:             100,
+			MaxEnumValues:         50,
+			ShareIdenticalSchemas: false,
+			ServiceCatalogTool:    false,
+			EmitTitles:            false,
+			MaxDefinitions:        50,
+			DefinitionOverflow:    "truncate",
+			FallbackDescription:   "Calls the {method} method of the {service} service",
 		},
 		Logging: LoggingConfig{
 			Level:       "info",
@@ -338,6 +478,18 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("server timeout must be positive")
 	}
 
+	if c.Server.MaxConnections < 0 {
+		return fmt.Errorf("max connections cannot be negative")
+	}
+
+	if c.Server.Admin.Port < 0 || c.Server.Admin.Port > 65535 {
+		return fmt.Errorf("invalid admin port: %d", c.Server.Admin.Port)
+	}
+
+	if c.Server.Admin.Port > 0 && c.Server.Admin.Token == "" {
+		return fmt.Errorf("admin token is required when the admin port is set")
+	}
+
 	if c.GRPC.ConnectTimeout <= 0 {
 		return fmt.Errorf("gRPC connect timeout must be positive")
 	}
@@ -346,11 +498,38 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("max sessions must be positive")
 	}
 
+	if c.Session.DedupWindow < 0 {
+		return fmt.Errorf("session dedup window cannot be negative")
+	}
+
+	switch c.Tools.DefinitionOverflow {
+	case "", "truncate", "error":
+	default:
+		return fmt.Errorf("invalid definition overflow policy: %s", c.Tools.DefinitionOverflow)
+	}
+
+	if c.GRPC.Reconnect.HealthCheckInterval < 0 {
+		return fmt.Errorf("health check interval cannot be negative")
+	}
+
+	if c.GRPC.ServerStreaming.MaxMessages < 0 || c.GRPC.ServerStreaming.MaxBytes < 0 {
+		return fmt.Errorf("server streaming limits cannot be negative")
+	}
+
+	for service, target := range c.GRPC.InvocationTargets {
+		if target.Host == "" || target.Port <= 0 || target.Port > 65535 {
+			return fmt.Errorf("invalid invocation target for %s: %s:%d", service, target.Host, target.Port)
+		}
+	}
+
 	// Validate descriptor set configuration
 	if c.GRPC.DescriptorSet.Enabled {
 		if c.GRPC.DescriptorSet.Path == "" {
 			return fmt.Errorf("descriptor set path must be specified when enabled")
 		}
+		if c.GRPC.DescriptorSet.Retry.Backoff < 0 {
+			return fmt.Errorf("descriptor set retry backoff cannot be negative")
+		}
 	}
 
 	return nil
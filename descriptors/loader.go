@@ -1,11 +1,15 @@
 package descriptors
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/lysfighting/ggRMCP/config"
 	"github.com/lysfighting/ggRMCP/types"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
@@ -15,10 +19,30 @@ import (
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
+// FileSystem opens descriptor set files for reading
+type FileSystem interface {
+	Open(name string) (io.ReadCloser, error)
+}
+
+// osFileSystem reads descriptor set files from the local filesystem
+type osFileSystem struct{}
+
+// Open opens the named file
+func (osFileSystem) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
 // Loader handles loading and parsing FileDescriptorSet files
 type Loader struct {
 	logger *zap.Logger
 	files  *protoregistry.Files
+	fs     FileSystem
+
+	// Retry settings for transient read failures
+	retry config.RetryConfig
+
+	// Normalize path separators in file names and dependencies
+	normalizePaths bool
 }
 
 // NewLoader creates a new descriptor loader
@@ -26,28 +50,27 @@ func NewLoader(logger *zap.Logger) *Loader {
 	return &Loader{
 		logger: logger.Named("descriptors"),
 		files:  &protoregistry.Files{},
+		fs:     osFileSystem{},
+
+		normalizePaths: true,
 	}
 }
 
+// NewLoaderWithConfig creates a new descriptor loader from descriptor set configuration
+func NewLoaderWithConfig(logger *zap.Logger, cfg config.DescriptorSetConfig) *Loader {
+	l := NewLoader(logger)
+	l.retry = cfg.Retry
+	l.normalizePaths = cfg.NormalizePaths
+	return l
+}
+
 // LoadFromFile loads a FileDescriptorSet from a binary protobuf file
 func (l *Loader) LoadFromFile(path string) (*descriptorpb.FileDescriptorSet, error) {
 	l.logger.Info("Loading FileDescriptorSet", zap.String("path", path))
 
-	// Open the file
-	file, err := os.Open(path)
+	data, err := l.readFileWithRetry(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open descriptor file %s: %w", path, err)
-	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			// File close errors are typically not critical in read operations
-		}
-	}()
-
-	// Read the binary content
-	data, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read descriptor file %s: %w", path, err)
+		return nil, err
 	}
 
 	// Parse the FileDescriptorSet
@@ -63,10 +86,71 @@ func (l *Loader) LoadFromFile(path string) (*descriptorpb.FileDescriptorSet, err
 	return &fdSet, nil
 }
 
+// readFileWithRetry reads a file, retrying transient failures with exponential backoff
+func (l *Loader) readFileWithRetry(path string) ([]byte, error) {
+	maxAttempts := l.retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	backoff := l.retry.Backoff
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		data, err := l.readFile(path)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+
+		// Missing files and permission problems will not resolve themselves
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) || attempt == maxAttempts {
+			break
+		}
+
+		l.logger.Warn("Failed to read descriptor file, retrying",
+			zap.String("path", path),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return nil, lastErr
+}
+
+// readFile reads the full content of a file
+func (l *Loader) readFile(path string) ([]byte, error) {
+	file, err := l.fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open descriptor file %s: %w", path, err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			l.logger.Debug("Failed to close descriptor file",
+				zap.String("path", path),
+				zap.Error(err))
+		}
+	}()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor file %s: %w", path, err)
+	}
+
+	return data, nil
+}
+
 // BuildRegistry creates a protoregistry.Files from a FileDescriptorSet
 func (l *Loader) BuildRegistry(fdSet *descriptorpb.FileDescriptorSet) (*protoregistry.Files, error) {
 	files := &protoregistry.Files{}
 
+	// Dependencies are matched by exact name, so unify separators first
+	if l.normalizePaths {
+		fdSet = normalizeFilePaths(fdSet)
+	}
+
 	// Process files in dependency order
 	processed := make(map[string]bool)
 	var processFile func(*descriptorpb.FileDescriptorProto) error
@@ -145,10 +229,9 @@ func (l *Loader) ExtractMethodInfo(files *protoregistry.Files) ([]types.MethodIn
 		for i := 0; i < fd.Services().Len(); i++ {
 			serviceDesc := fd.Services().Get(i)
 
-			// Extract service name to match reflection discovery format
-			// Convert from "com.example.hello.HelloService" to "hello.HelloService"
-			fullName := string(serviceDesc.FullName())
-			serviceName := extractServiceNameForCompatibility(fullName)
+			// Build the service name from the package exactly as reflection discovery does
+			packageName := string(fd.Package())
+			serviceName := qualifiedServiceName(packageName, string(serviceDesc.Name()))
 			serviceDescription := extractComments(serviceDesc)
 
 			// Process each method in the service and add directly to flat list
@@ -160,6 +243,8 @@ func (l *Loader) ExtractMethodInfo(files *protoregistry.Files) ([]types.MethodIn
 					FullName:           string(methodDesc.FullName()),
 					ServiceName:        serviceName,
 					ServiceDescription: serviceDescription,
+					PackageName:        packageName,
+					FileName:           fd.Path(),
 					Description:        extractComments(methodDesc),
 					InputType:          string(methodDesc.Input().FullName()),
 					OutputType:         string(methodDesc.Output().FullName()),
@@ -215,21 +300,32 @@ func extractComments(desc protoreflect.Descriptor) string {
 	return comments
 }
 
-// extractServiceNameForCompatibility extracts service name to match reflection format
-// Converts "com.example.hello.HelloService" to "hello.HelloService"
-// This ensures compatibility between FileDescriptorSet and reflection discovery
-func extractServiceNameForCompatibility(fullName string) string {
-	// Split the full name by dots
-	parts := strings.Split(fullName, ".")
-	if len(parts) < 2 {
-		// If there's no package, return as-is
-		return fullName
+// qualifiedServiceName joins a proto package and service name into the
+// fully qualified service name used by reflection discovery
+// (e.g., "com.example.hello" + "HelloService" -> "com.example.hello.HelloService")
+func qualifiedServiceName(packageName, serviceName string) string {
+	if packageName == "" {
+		return serviceName
 	}
+	return packageName + "." + serviceName
+}
 
-	// Take the last two parts (package.Service) to match reflection format
-	// e.g., "com.example.hello.HelloService" -> "hello.HelloService"
-	packageName := parts[len(parts)-2]
-	serviceName := parts[len(parts)-1]
+// normalizeFilePaths returns a copy of the set with backslash separators in file names
+// and dependencies replaced by "/"
+func normalizeFilePaths(fdSet *descriptorpb.FileDescriptorSet) *descriptorpb.FileDescriptorSet {
+	normalized := proto.Clone(fdSet).(*descriptorpb.FileDescriptorSet)
+	for _, fdProto := range normalized.File {
+		if fdProto.Name != nil {
+			fdProto.Name = proto.String(filepathToSlash(fdProto.GetName()))
+		}
+		for i, dep := range fdProto.Dependency {
+			fdProto.Dependency[i] = filepathToSlash(dep)
+		}
+	}
+	return normalized
+}
 
-	return fmt.Sprintf("%s.%s", packageName, serviceName)
+// filepathToSlash replaces backslash separators with "/" regardless of the host OS
+func filepathToSlash(path string) string {
+	return strings.ReplaceAll(path, "\\", "/")
 }
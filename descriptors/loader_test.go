@@ -0,0 +1,195 @@
+package descriptors
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// newNestedPackageDescriptorSet builds a FileDescriptorSet whose service lives in a multi-part package
+func newNestedPackageDescriptorSet() *descriptorpb.FileDescriptorSet {
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("com/example/nested/v1/orders.proto"),
+				Package: proto.String("com.example.nested.v1"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("GetOrderRequest"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							{
+								Name:     proto.String("order_id"),
+								JsonName: proto.String("orderId"),
+								Number:   proto.Int32(1),
+								Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+								Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+							},
+						},
+					},
+					{Name: proto.String("GetOrderResponse")},
+				},
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{
+						Name: proto.String("OrderService"),
+						Method: []*descriptorpb.MethodDescriptorProto{
+							{
+								Name:       proto.String("GetOrder"),
+								InputType:  proto.String(".com.example.nested.v1.GetOrderRequest"),
+								OutputType: proto.String(".com.example.nested.v1.GetOrderResponse"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExtractMethodInfo_NestedPackage(t *testing.T) {
+	loader := NewLoader(zap.NewNop())
+
+	files, err := loader.BuildRegistry(newNestedPackageDescriptorSet())
+	require.NoError(t, err)
+
+	methods, err := loader.ExtractMethodInfo(files)
+	require.NoError(t, err)
+	require.Len(t, methods, 1)
+
+	method := methods[0]
+	assert.Equal(t, "com.example.nested.v1", method.PackageName)
+	assert.Equal(t, "com/example/nested/v1/orders.proto", method.FileName)
+
+	// The service name keeps the full package so it matches reflection discovery
+	assert.Equal(t, "com.example.nested.v1.OrderService", method.ServiceName)
+	assert.Equal(t, "com.example.nested.v1.OrderService.GetOrder", method.FullName)
+	assert.Equal(t, "com_example_nested_v1_orderservice_getorder", method.ToolName)
+}
+
+// flakyFileSystem fails the first failures opens with err and then serves data
+type flakyFileSystem struct {
+	data     []byte
+	failures int
+	err      error
+	opens    int
+}
+
+func (f *flakyFileSystem) Open(name string) (io.ReadCloser, error) {
+	f.opens++
+	if f.opens <= f.failures {
+		return nil, f.err
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+func TestLoadFromFile_RetriesTransientErrors(t *testing.T) {
+	data, err := proto.Marshal(newNestedPackageDescriptorSet())
+	require.NoError(t, err)
+
+	descriptorConfig := config.DescriptorSetConfig{
+		Retry: config.RetryConfig{MaxAttempts: 3, Backoff: time.Millisecond},
+	}
+	transient := errors.New("stale NFS file handle")
+
+	t.Run("succeeds after a transient failure", func(t *testing.T) {
+		flaky := &flakyFileSystem{data: data, failures: 1, err: transient}
+		loader := NewLoaderWithConfig(zap.NewNop(), descriptorConfig)
+		loader.fs = flaky
+
+		fdSet, err := loader.LoadFromFile("orders.binpb")
+		require.NoError(t, err)
+		assert.Len(t, fdSet.File, 1)
+		assert.Equal(t, 2, flaky.opens)
+	})
+
+	t.Run("fails without retry configured", func(t *testing.T) {
+		flaky := &flakyFileSystem{data: data, failures: 1, err: transient}
+		loader := NewLoader(zap.NewNop())
+		loader.fs = flaky
+
+		_, err := loader.LoadFromFile("orders.binpb")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, transient)
+		assert.Equal(t, 1, flaky.opens)
+	})
+
+	t.Run("does not retry missing files", func(t *testing.T) {
+		flaky := &flakyFileSystem{data: data, failures: 1, err: fs.ErrNotExist}
+		loader := NewLoaderWithConfig(zap.NewNop(), descriptorConfig)
+		loader.fs = flaky
+
+		_, err := loader.LoadFromFile("orders.binpb")
+		require.Error(t, err)
+		assert.Equal(t, 1, flaky.opens)
+	})
+}
+
+// newMixedSeparatorDescriptorSet returns a set whose dependency is declared with "/"
+// while the dependent file is named with "\"
+func newMixedSeparatorDescriptorSet() *descriptorpb.FileDescriptorSet {
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:       proto.String(`api\orders.proto`),
+				Package:    proto.String("test.paths"),
+				Syntax:     proto.String("proto3"),
+				Dependency: []string{"common/money.proto"},
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Order"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							{
+								Name:     proto.String("total"),
+								Number:   proto.Int32(1),
+								Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+								Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+								TypeName: proto.String(".test.paths.Money"),
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:    proto.String(`common\money.proto`),
+				Package: proto.String("test.paths"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{Name: proto.String("Money")},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildRegistry_NormalizesPathSeparators(t *testing.T) {
+	fdSet := newMixedSeparatorDescriptorSet()
+
+	loader := NewLoaderWithConfig(zap.NewNop(), config.Default().GRPC.DescriptorSet)
+	files, err := loader.BuildRegistry(fdSet)
+	require.NoError(t, err)
+
+	fd, err := files.FindFileByPath("api/orders.proto")
+	require.NoError(t, err)
+	assert.Equal(t, "common/money.proto", fd.Imports().Get(0).Path())
+	assert.Equal(t, "test.paths.Money",
+		string(fd.Messages().ByName("Order").Fields().ByName("total").Message().FullName()))
+
+	// The input set is left untouched
+	assert.Equal(t, `api\orders.proto`, fdSet.File[0].GetName())
+
+	// Without normalization the dependency cannot be resolved
+	cfg := config.Default().GRPC.DescriptorSet
+	cfg.NormalizePaths = false
+	_, err = NewLoaderWithConfig(zap.NewNop(), cfg).BuildRegistry(fdSet)
+	assert.Error(t, err)
+}
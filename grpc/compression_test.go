@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	grpcLib "google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// countingCompressor is a pass-through compressor that counts how often it is used
+type countingCompressor struct {
+	compressed atomic.Int64
+}
+
+func (c *countingCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	c.compressed.Add(1)
+	return nopWriteCloser{w}, nil
+}
+
+func (c *countingCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return r, nil
+}
+
+func (c *countingCompressor) Name() string {
+	return "counting"
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// testCompressor is registered at init time, as encoding.RegisterCompressor requires
+var testCompressor = &countingCompressor{}
+
+func init() {
+	encoding.RegisterCompressor(testCompressor)
+}
+
+func TestInvokeMethod_PerCallCompression(t *testing.T) {
+	compressor := testCompressor
+
+	fd := newStreamTestFile(t)
+	conn := startBufconnServer(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "compressed")
+	})
+
+	client := &reflectionClient{
+		conn:    conn,
+		logger:  zap.NewNop(),
+		fdCache: make(map[string]*descriptorpb.FileDescriptorProto),
+	}
+	method := countMethodInfo(fd, "Echo", false)
+
+	// A call selecting the compressor uses it
+	ctx := WithInvokeOptions(context.Background(), types.InvokeOptions{Compression: "counting"})
+	result, err := client.InvokeMethod(ctx, nil, method, `{"count":1}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"label":"compressed"}`, result)
+	compressedCalls := compressor.compressed.Load()
+	assert.Positive(t, compressedCalls)
+
+	// Other calls on the same connection are unaffected
+	result, err = client.InvokeMethod(context.Background(), nil, method, `{"count":1}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"label":"compressed"}`, result)
+	assert.Equal(t, compressedCalls, compressor.compressed.Load())
+
+	// Unknown compressors are rejected before the call is made
+	ctx = WithInvokeOptions(context.Background(), types.InvokeOptions{Compression: "brotli"})
+	_, err = client.InvokeMethod(ctx, nil, method, `{"count":1}`)
+	assert.ErrorContains(t, err, "unsupported compression")
+}
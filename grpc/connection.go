@@ -43,6 +43,19 @@ func (cm *connectionManager) Connect(ctx context.Context) error {
 	target := fmt.Sprintf("%s:%d", cm.config.Host, cm.config.Port)
 	cm.logger.Info("Connecting to gRPC server", zap.String("target", target))
 
+	callOpts := []grpcLib.CallOption{
+		grpcLib.MaxCallRecvMsgSize(cm.config.MaxMessageSize),
+		grpcLib.MaxCallSendMsgSize(cm.config.MaxMessageSize),
+	}
+
+	// Compress every call by default when configured; calls can still override it
+	if cm.config.Compression != "" {
+		if err := validateCompressor(cm.config.Compression); err != nil {
+			return err
+		}
+		callOpts = append(callOpts, grpcLib.UseCompressor(cm.config.Compression))
+	}
+
 	// Configure connection options
 	opts := []grpcLib.DialOption{
 		grpcLib.WithTransportCredentials(insecure.NewCredentials()),
@@ -51,11 +64,9 @@ func (cm *connectionManager) Connect(ctx context.Context) error {
 			Timeout:             cm.config.KeepAlive.Timeout,
 			PermitWithoutStream: cm.config.KeepAlive.PermitWithoutStream,
 		}),
-		grpcLib.WithDefaultCallOptions(
-			grpcLib.MaxCallRecvMsgSize(cm.config.MaxMessageSize),
-			grpcLib.MaxCallSendMsgSize(cm.config.MaxMessageSize),
-		),
+		grpcLib.WithDefaultCallOptions(callOpts...),
 	}
+	opts = append(opts, cm.config.DialOptions...)
 
 	// Create context with timeout
 	connectCtx, cancel := context.WithTimeout(ctx, cm.config.ConnectTimeout)
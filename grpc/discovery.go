@@ -2,7 +2,9 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -12,46 +14,108 @@ import (
 	"go.uber.org/zap"
 )
 
+// allServicesTarget is the invocation target key matching every service
+const allServicesTarget = "*"
+
+// ErrNoServices is returned by DiscoverServices in strict mode when the server exposes no user services
+var ErrNoServices = errors.New("gRPC server exposes no services")
+
+// ErrPermanentFailure is returned once reconnect attempts are exhausted, until Refresh succeeds
+var ErrPermanentFailure = errors.New("gRPC connection permanently failed; admin refresh required")
+
 // serviceDiscoverer implements ServiceDiscoverer interface
 // Similar to Java ServiceDiscoverer - handles both reflection and file descriptor cases
 type serviceDiscoverer struct {
 	logger           *zap.Logger
 	connManager      ConnectionManager
 	reflectionClient ReflectionClient
+	clientMu         sync.RWMutex
 	tools            atomic.Pointer[map[string]types.MethodInfo]
 
+	// Serializes reconnects triggered by the health monitor and by Refresh
+	reconnectMu sync.Mutex
+
+	// Stops the background health monitor started by Connect
+	stopMonitor context.CancelFunc
+
 	// Method extraction components
 	descriptorLoader *descriptors.Loader
 	descriptorConfig config.DescriptorSetConfig
 
+	// Connections used for invocation instead of the discovery connection,
+	// keyed by full service name or "*"
+	invocationTargets map[string]ConnectionManagerConfig
+	invocation        *invocationSet
+	invocationMu      sync.RWMutex
+
+	// Set when reconnect attempts are exhausted and cleared only by Refresh
+	permanentFailure      error
+	permanentFailureCount int64
+	failureMu             sync.RWMutex
+
 	// Configuration
 	reconnectInterval    time.Duration
 	maxReconnectAttempts int
+	healthCheckInterval  time.Duration
+	strictDiscovery      bool
+	serverStreaming      config.ServerStreamingConfig
+}
+
+// invocationSet is one generation of invocation target connections
+type invocationSet struct {
+	conns   map[string]ConnectionManager
+	clients map[string]ReflectionClient
+
+	// Calls in flight on this generation; its connections are closed only once they finish
+	inFlight sync.WaitGroup
 }
 
 // NewServiceDiscoverer creates a new service discoverer with descriptor support
 func NewServiceDiscoverer(host string, port int, logger *zap.Logger, descriptorConfig config.DescriptorSetConfig) (ServiceDiscoverer, error) {
+	grpcConfig := config.Default().GRPC
+	grpcConfig.Host = host
+	grpcConfig.Port = port
+	grpcConfig.DescriptorSet = descriptorConfig
+
+	return NewServiceDiscovererWithConfig(logger, grpcConfig)
+}
+
+// NewServiceDiscovererWithConfig creates a new service discoverer from gRPC configuration
+func NewServiceDiscovererWithConfig(logger *zap.Logger, cfg config.GRPCConfig) (ServiceDiscoverer, error) {
 	baseConfig := ConnectionManagerConfig{
-		Host:           host,
-		Port:           port,
-		ConnectTimeout: 5 * time.Second,
+		Host:           cfg.Host,
+		Port:           cfg.Port,
+		ConnectTimeout: cfg.ConnectTimeout,
 		KeepAlive: KeepAliveConfig{
-			Time:                10 * time.Second,
-			Timeout:             5 * time.Second,
-			PermitWithoutStream: true,
+			Time:                cfg.KeepAlive.Time,
+			Timeout:             cfg.KeepAlive.Timeout,
+			PermitWithoutStream: cfg.KeepAlive.PermitWithoutStream,
 		},
-		MaxMessageSize: 4 * 1024 * 1024, // 4MB
+		MaxMessageSize: cfg.MaxMessageSize,
+		Compression:    cfg.Compression,
 	}
 
 	connManager := NewConnectionManager(baseConfig, logger)
 
+	invocationTargets := make(map[string]ConnectionManagerConfig, len(cfg.InvocationTargets))
+	for service, target := range cfg.InvocationTargets {
+		targetConfig := baseConfig
+		targetConfig.Host = target.Host
+		targetConfig.Port = target.Port
+		invocationTargets[service] = targetConfig
+	}
+
 	d := &serviceDiscoverer{
 		logger:               logger.Named("discovery"),
 		connManager:          connManager,
-		descriptorLoader:     descriptors.NewLoader(logger),
-		descriptorConfig:     descriptorConfig,
-		reconnectInterval:    5 * time.Second,
-		maxReconnectAttempts: 5,
+		descriptorLoader:     descriptors.NewLoaderWithConfig(logger, cfg.DescriptorSet),
+		descriptorConfig:     cfg.DescriptorSet,
+		invocationTargets:    invocationTargets,
+		reconnectInterval:    cfg.Reconnect.Interval,
+		maxReconnectAttempts: cfg.Reconnect.MaxAttempts,
+		healthCheckInterval:  cfg.Reconnect.HealthCheckInterval,
+		strictDiscovery:      cfg.StrictDiscovery,
+		serverStreaming:      cfg.ServerStreaming,
 	}
 
 	// Initialize with empty tools map
@@ -76,20 +140,164 @@ func (d *serviceDiscoverer) Connect(ctx context.Context) error {
 		return fmt.Errorf("connection manager returned nil connection")
 	}
 
-	d.reflectionClient = NewReflectionClient(conn, d.logger)
+	client := newReflectionClient(conn, d.logger, d.serverStreaming)
+	d.setReflectionClient(client)
 
 	// Verify connection with health check
-	if err := d.reflectionClient.HealthCheck(ctx); err != nil {
+	if err := client.HealthCheck(ctx); err != nil {
 		return fmt.Errorf("health check failed: %w", err)
 	}
 
+	if err := d.connectInvocationTargets(ctx); err != nil {
+		return err
+	}
+
+	d.startHealthMonitor()
+
 	d.logger.Info("Successfully connected to gRPC server")
 	return nil
 }
 
+// getReflectionClient returns the client for the discovery connection, or nil when not connected
+func (d *serviceDiscoverer) getReflectionClient() ReflectionClient {
+	d.clientMu.RLock()
+	defer d.clientMu.RUnlock()
+	return d.reflectionClient
+}
+
+// setReflectionClient replaces the client for the discovery connection
+func (d *serviceDiscoverer) setReflectionClient(client ReflectionClient) {
+	d.clientMu.Lock()
+	defer d.clientMu.Unlock()
+	d.reflectionClient = client
+}
+
+// startHealthMonitor starts the background health monitor once, if enabled
+func (d *serviceDiscoverer) startHealthMonitor() {
+	if d.healthCheckInterval <= 0 {
+		return
+	}
+
+	d.clientMu.Lock()
+	defer d.clientMu.Unlock()
+	if d.stopMonitor != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopMonitor = cancel
+	go d.monitorHealth(ctx)
+}
+
+// monitorHealth periodically checks the connection and reconnects when it is unhealthy,
+// entering the permanent failure state once reconnect attempts are exhausted
+func (d *serviceDiscoverer) monitorHealth(ctx context.Context) {
+	ticker := time.NewTicker(d.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Only an admin refresh leaves the permanent failure state
+		if d.permanentFailureError() != nil {
+			continue
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, d.healthCheckInterval)
+		err := d.HealthCheck(checkCtx)
+		cancel()
+		if err == nil {
+			continue
+		}
+
+		d.logger.Warn("gRPC health check failed, reconnecting", zap.Error(err))
+		if err := d.Reconnect(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error("Failed to recover gRPC connection", zap.Error(err))
+		}
+	}
+}
+
+// connectInvocationTargets connects fresh connections to the configured invocation targets
+// and swaps them in only once all succeed, then closes the previous connections.
+// On failure the previous connections stay in use.
+func (d *serviceDiscoverer) connectInvocationTargets(ctx context.Context) error {
+	conns := make(map[string]ConnectionManager, len(d.invocationTargets))
+	clients := make(map[string]ReflectionClient, len(d.invocationTargets))
+	for service, targetConfig := range d.invocationTargets {
+		connManager := NewConnectionManager(targetConfig, d.logger)
+		if err := connManager.Connect(ctx); err != nil {
+			d.closeInvocationConns(conns)
+			return fmt.Errorf("failed to connect invocation target for %s: %w", service, err)
+		}
+		conns[service] = connManager
+
+		conn := connManager.GetConnection()
+		if conn == nil {
+			d.closeInvocationConns(conns)
+			return fmt.Errorf("connection manager returned nil connection for invocation target %s", service)
+		}
+		clients[service] = newReflectionClient(conn, d.logger, d.serverStreaming)
+
+		d.logger.Info("Connected invocation target", zap.String("service", service))
+	}
+
+	d.invocationMu.Lock()
+	previous := d.invocation
+	d.invocation = &invocationSet{conns: conns, clients: clients}
+	d.invocationMu.Unlock()
+
+	if previous != nil {
+		go d.drainInvocationSet(previous)
+	}
+	return nil
+}
+
+// drainInvocationSet closes a replaced generation of invocation connections once the
+// calls still using it have finished
+func (d *serviceDiscoverer) drainInvocationSet(set *invocationSet) {
+	set.inFlight.Wait()
+	d.closeInvocationConns(set.conns)
+}
+
+// closeInvocationConns closes invocation target connections that are no longer in use
+func (d *serviceDiscoverer) closeInvocationConns(conns map[string]ConnectionManager) {
+	for service, connManager := range conns {
+		if err := connManager.Close(); err != nil {
+			d.logger.Error("Failed to close invocation target",
+				zap.String("service", service),
+				zap.Error(err))
+		}
+	}
+}
+
+// invocationClient returns the client used to invoke methods of a service,
+// preferring a service-specific target, then the "*" target, then the discovery connection.
+// The caller must call release once the call has finished.
+func (d *serviceDiscoverer) invocationClient(serviceName string) (client ReflectionClient, release func()) {
+	d.invocationMu.RLock()
+	defer d.invocationMu.RUnlock()
+
+	if set := d.invocation; set != nil {
+		client, exists := set.clients[serviceName]
+		if !exists {
+			client, exists = set.clients[allServicesTarget]
+		}
+		if exists {
+			set.inFlight.Add(1)
+			return client, set.inFlight.Done
+		}
+	}
+	return d.getReflectionClient(), func() {}
+}
+
 // DiscoverServices discovers all available gRPC services
 func (d *serviceDiscoverer) DiscoverServices(ctx context.Context) error {
-	if d.reflectionClient == nil {
+	client := d.getReflectionClient()
+	if client == nil {
 		return fmt.Errorf("not connected to gRPC server")
 	}
 
@@ -112,12 +320,20 @@ func (d *serviceDiscoverer) DiscoverServices(ctx context.Context) error {
 
 	// Use reflection discovery if FileDescriptorSet failed or wasn't enabled
 	if methods == nil {
-		methods, err = d.discoverFromReflection(ctx)
+		methods, err = d.discoverFromReflection(ctx, client)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Reflection can succeed while the server registers no user services
+	if len(methods) == 0 {
+		if d.strictDiscovery {
+			return ErrNoServices
+		}
+		d.logger.Warn("Service discovery found no services; no tools will be exposed")
+	}
+
 	// Set the discovered tools
 	tools := make(map[string]types.MethodInfo)
 	for _, method := range methods {
@@ -155,10 +371,10 @@ func (d *serviceDiscoverer) discoverFromFileDescriptor() ([]types.MethodInfo, er
 }
 
 // discoverFromReflection discovers services from reflection
-func (d *serviceDiscoverer) discoverFromReflection(ctx context.Context) ([]types.MethodInfo, error) {
+func (d *serviceDiscoverer) discoverFromReflection(ctx context.Context, client ReflectionClient) ([]types.MethodInfo, error) {
 	d.logger.Info("Discovering services from reflection")
 
-	methods, err := d.reflectionClient.DiscoverMethods(ctx)
+	methods, err := client.DiscoverMethods(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover services via reflection: %w", err)
 	}
@@ -183,8 +399,13 @@ func (d *serviceDiscoverer) GetMethods() []types.MethodInfo {
 	return methods
 }
 
-// Reconnect attempts to reconnect to the gRPC server
+// Reconnect attempts to reconnect to the gRPC server, entering the permanent failure
+// state once maxReconnectAttempts are exhausted
 func (d *serviceDiscoverer) Reconnect(ctx context.Context) error {
+	if err := d.permanentFailureError(); err != nil {
+		return err
+	}
+
 	d.logger.Info("Attempting to reconnect to gRPC server")
 
 	var lastErr error
@@ -201,8 +422,7 @@ func (d *serviceDiscoverer) Reconnect(ctx context.Context) error {
 			}
 		}
 
-		// Use connection manager to reconnect
-		if err := d.connManager.Reconnect(ctx); err != nil {
+		if err := d.reconnectOnce(ctx); err != nil {
 			lastErr = err
 			d.logger.Warn("Reconnect attempt failed",
 				zap.Int("attempt", i+1),
@@ -210,56 +430,121 @@ func (d *serviceDiscoverer) Reconnect(ctx context.Context) error {
 			continue
 		}
 
-		// Recreate reflection client with new connection
-		conn := d.connManager.GetConnection()
-		if conn == nil {
-			lastErr = fmt.Errorf("connection manager returned nil connection after reconnect")
-			continue
-		}
-		d.reflectionClient = NewReflectionClient(conn, d.logger)
-
-		// Rediscover services after reconnection
-		if err := d.DiscoverServices(ctx); err != nil {
-			lastErr = err
-			d.logger.Warn("Service rediscovery failed",
-				zap.Int("attempt", i+1),
-				zap.Error(err))
-			continue
-		}
-
 		d.logger.Info("Successfully reconnected to gRPC server")
 		return nil
 	}
 
-	return fmt.Errorf("failed to reconnect after %d attempts: %w", d.maxReconnectAttempts, lastErr)
+	failure := fmt.Errorf("%w: failed to reconnect after %d attempts: %w",
+		ErrPermanentFailure, d.maxReconnectAttempts, lastErr)
+	d.setPermanentFailure(failure)
+	d.logger.Error("Reconnect attempts exhausted, gRPC connection marked as permanently failed",
+		zap.Int("maxAttempts", d.maxReconnectAttempts),
+		zap.Error(lastErr))
+
+	return failure
+}
+
+// Refresh makes a single reconnect and rediscovery attempt, clearing the permanent failure state on success
+func (d *serviceDiscoverer) Refresh(ctx context.Context) error {
+	d.logger.Info("Refreshing gRPC connection")
+
+	if err := d.reconnectOnce(ctx); err != nil {
+		return fmt.Errorf("refresh failed: %w", err)
+	}
+
+	d.failureMu.Lock()
+	recovered := d.permanentFailure != nil
+	d.permanentFailure = nil
+	d.failureMu.Unlock()
+
+	d.logger.Info("Successfully refreshed gRPC connection", zap.Bool("recovered", recovered))
+	return nil
+}
+
+// reconnectOnce reconnects all connections and rediscovers services
+func (d *serviceDiscoverer) reconnectOnce(ctx context.Context) error {
+	d.reconnectMu.Lock()
+	defer d.reconnectMu.Unlock()
+
+	// Use connection manager to reconnect
+	if err := d.connManager.Reconnect(ctx); err != nil {
+		return err
+	}
+
+	// Recreate reflection client with new connection
+	conn := d.connManager.GetConnection()
+	if conn == nil {
+		return fmt.Errorf("connection manager returned nil connection after reconnect")
+	}
+	d.setReflectionClient(newReflectionClient(conn, d.logger, d.serverStreaming))
+
+	if err := d.connectInvocationTargets(ctx); err != nil {
+		return fmt.Errorf("invocation target reconnect failed: %w", err)
+	}
+
+	// Rediscover services after reconnection
+	if err := d.DiscoverServices(ctx); err != nil {
+		return fmt.Errorf("service rediscovery failed: %w", err)
+	}
+
+	return nil
+}
+
+// setPermanentFailure records that reconnect attempts were exhausted
+func (d *serviceDiscoverer) setPermanentFailure(err error) {
+	d.failureMu.Lock()
+	defer d.failureMu.Unlock()
+
+	d.permanentFailure = err
+	d.permanentFailureCount++
+}
+
+// permanentFailureError returns the permanent failure error, or nil while not in that state
+func (d *serviceDiscoverer) permanentFailureError() error {
+	d.failureMu.RLock()
+	defer d.failureMu.RUnlock()
+	return d.permanentFailure
 }
 
 // isConnected checks if the discoverer is connected (private helper)
 func (d *serviceDiscoverer) isConnected() bool {
-	return d.connManager.IsConnected() && d.reflectionClient != nil
+	return d.permanentFailureError() == nil && d.connManager.IsConnected() && d.getReflectionClient() != nil
 }
 
 // HealthCheck performs a health check
 func (d *serviceDiscoverer) HealthCheck(ctx context.Context) error {
+	if err := d.permanentFailureError(); err != nil {
+		return err
+	}
+
 	// Check connection manager health first
 	if err := d.connManager.HealthCheck(ctx); err != nil {
 		return fmt.Errorf("connection manager health check failed: %w", err)
 	}
 
-	if d.reflectionClient == nil {
+	client := d.getReflectionClient()
+	if client == nil {
 		return fmt.Errorf("reflection client not initialized")
 	}
 
-	return d.reflectionClient.HealthCheck(ctx)
+	return client.HealthCheck(ctx)
 }
 
 // Close closes the service discoverer
 func (d *serviceDiscoverer) Close() error {
-	if d.reflectionClient != nil {
-		if err := d.reflectionClient.Close(); err != nil {
+	d.clientMu.Lock()
+	client := d.reflectionClient
+	d.reflectionClient = nil
+	if d.stopMonitor != nil {
+		d.stopMonitor()
+		d.stopMonitor = nil
+	}
+	d.clientMu.Unlock()
+
+	if client != nil {
+		if err := client.Close(); err != nil {
 			d.logger.Error("Failed to close reflection client", zap.Error(err))
 		}
-		d.reflectionClient = nil
 	}
 
 	// Close connection manager
@@ -267,6 +552,15 @@ func (d *serviceDiscoverer) Close() error {
 		d.logger.Error("Failed to close connection manager", zap.Error(err))
 	}
 
+	// Close invocation target connections
+	d.invocationMu.Lock()
+	set := d.invocation
+	d.invocation = nil
+	d.invocationMu.Unlock()
+	if set != nil {
+		d.closeInvocationConns(set.conns)
+	}
+
 	// Reset tools to empty map
 	emptyMap := make(map[string]types.MethodInfo)
 	d.tools.Store(&emptyMap)
@@ -309,6 +603,7 @@ func (d *serviceDiscoverer) GetServiceStats() map[string]interface{} {
 			"isConnected":  d.isConnected(),
 			"services":     []string{},
 		}
+		d.addFailureStats(stats)
 		return stats
 	}
 
@@ -328,10 +623,23 @@ func (d *serviceDiscoverer) GetServiceStats() map[string]interface{} {
 		"isConnected":  d.isConnected(),
 		"services":     serviceList,
 	}
+	d.addFailureStats(stats)
 
 	return stats
 }
 
+// addFailureStats adds the permanent failure state and metric to service statistics
+func (d *serviceDiscoverer) addFailureStats(stats map[string]interface{}) {
+	d.failureMu.RLock()
+	defer d.failureMu.RUnlock()
+
+	stats["permanentFailure"] = d.permanentFailure != nil
+	stats["permanentFailureCount"] = d.permanentFailureCount
+	if d.permanentFailure != nil {
+		stats["lastFailure"] = d.permanentFailure.Error()
+	}
+}
+
 // getMethodByTool returns information about a method by its tool name (private helper)
 func (d *serviceDiscoverer) getMethodByTool(toolName string) (types.MethodInfo, bool) {
 	tools := d.tools.Load()
@@ -350,23 +658,34 @@ func (d *serviceDiscoverer) InvokeMethodByTool(ctx context.Context, headers map[
 		return "", fmt.Errorf("tool %s not found", toolName)
 	}
 
-	// Check for streaming methods (not supported in this implementation)
-	if method.IsClientStreaming || method.IsServerStreaming {
-		return "", fmt.Errorf("streaming methods are not supported")
+	// Client and bidirectional streaming are not supported; server streaming is aggregated when enabled
+	if method.IsClientStreaming {
+		return "", fmt.Errorf("client streaming methods are not supported")
+	}
+	if method.IsServerStreaming && !d.serverStreaming.Enabled {
+		return "", fmt.Errorf("server streaming methods are not enabled")
 	}
 
-	if d.reflectionClient == nil {
+	if err := d.permanentFailureError(); err != nil {
+		return "", err
+	}
+
+	if d.getReflectionClient() == nil {
 		return "", fmt.Errorf("not connected to gRPC server")
 	}
 
+	// Only the input size is logged; the handler logs the arguments after redaction
 	d.logger.Debug("Invoking gRPC method by tool",
 		zap.String("toolName", toolName),
 		zap.String("service", method.FullName),
 		zap.Int("headerCount", len(headers)),
-		zap.String("input", inputJSON))
+		zap.Int("inputSize", len(inputJSON)))
+
+	// Invoke the method through the reflection client for the service's target
+	client, release := d.invocationClient(method.ServiceName)
+	defer release()
 
-	// Invoke the method through the reflection client
-	result, err := d.reflectionClient.InvokeMethod(ctx, headers, method, inputJSON)
+	result, err := client.InvokeMethod(ctx, headers, method, inputJSON)
 	if err != nil {
 		return "", fmt.Errorf("failed to invoke method: %w", err)
 	}
@@ -383,6 +702,7 @@ func newServiceDiscovererWithConnManager(connManager ConnectionManager, logger *
 		descriptorConfig:     config.DescriptorSetConfig{},
 		reconnectInterval:    5 * time.Second,
 		maxReconnectAttempts: 5,
+		serverStreaming:      config.Default().GRPC.ServerStreaming,
 	}
 
 	// Initialize with empty tools map
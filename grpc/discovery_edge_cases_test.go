@@ -60,9 +60,9 @@ func TestDiscoverMethods_MultipleServicesInOneFile(t *testing.T) {
 	assert.GreaterOrEqual(t, len(serviceNames), 3, "Should discover multiple services from one file")
 
 	expectedServices := []string{
-		"complex.UserProfileService",
-		"complex.DocumentService",
-		"complex.NodeService",
+		"com.example.complex.UserProfileService",
+		"com.example.complex.DocumentService",
+		"com.example.complex.NodeService",
 	}
 
 	for _, expectedService := range expectedServices {
@@ -71,9 +71,9 @@ func TestDiscoverMethods_MultipleServicesInOneFile(t *testing.T) {
 	}
 
 	// Verify each service has the expected methods
-	assert.Contains(t, getMethodNames(methodsByService["complex.UserProfileService"]), "GetUserProfile")
-	assert.Contains(t, getMethodNames(methodsByService["complex.DocumentService"]), "CreateDocument")
-	assert.Contains(t, getMethodNames(methodsByService["complex.NodeService"]), "ProcessNode")
+	assert.Contains(t, getMethodNames(methodsByService["com.example.complex.UserProfileService"]), "GetUserProfile")
+	assert.Contains(t, getMethodNames(methodsByService["com.example.complex.DocumentService"]), "CreateDocument")
+	assert.Contains(t, getMethodNames(methodsByService["com.example.complex.NodeService"]), "ProcessNode")
 
 	t.Logf("✅ Successfully discovered %d methods from %d services in one file", len(methods), len(serviceNames))
 }
@@ -143,6 +143,44 @@ func TestDiscoverMethods_NoPackage(t *testing.T) {
 	t.Logf("✅ Successfully discovered method from service without package: %s", method.FullName)
 }
 
+// TestDiscoverMethods_NestedPackage tests that package and file names are populated for nested packages
+func TestDiscoverMethods_NestedPackage(t *testing.T) {
+	logger := zap.NewNop()
+	client := &reflectionClient{
+		logger:  logger,
+		fdCache: make(map[string]*descriptorpb.FileDescriptorProto),
+	}
+
+	fileDescriptor := &descriptorpb.FileDescriptorProto{
+		Name:    stringPtr("com/example/nested/v1/orders.proto"),
+		Package: stringPtr("com.example.nested.v1"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: stringPtr("GetOrderRequest")},
+			{Name: stringPtr("GetOrderResponse")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: stringPtr("OrderService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       stringPtr("GetOrder"),
+						InputType:  stringPtr(".com.example.nested.v1.GetOrderRequest"),
+						OutputType: stringPtr(".com.example.nested.v1.GetOrderResponse"),
+					},
+				},
+			},
+		},
+	}
+
+	methods := client.extractMethodsFromFileDescriptor(context.Background(), fileDescriptor, []string{"com.example.nested.v1.OrderService"})
+	require.Len(t, methods, 1)
+
+	method := methods[0]
+	assert.Equal(t, "com.example.nested.v1", method.PackageName)
+	assert.Equal(t, "com/example/nested/v1/orders.proto", method.FileName)
+	assert.Equal(t, "com.example.nested.v1.OrderService", method.ServiceName)
+}
+
 // TestToolNameGeneration_EdgeCases tests tool name generation for various edge cases
 func TestToolNameGeneration_EdgeCases(t *testing.T) {
 	tests := []struct {
@@ -368,9 +406,9 @@ func TestComplexServiceCoverage(t *testing.T) {
 
 	// Test the same expectations as the integration tests
 	expectedMethodsByService := map[string]string{
-		"complex.UserProfileService": "GetUserProfile",
-		"complex.DocumentService":    "CreateDocument",
-		"complex.NodeService":        "ProcessNode",
+		"com.example.complex.UserProfileService": "GetUserProfile",
+		"com.example.complex.DocumentService":    "CreateDocument",
+		"com.example.complex.NodeService":        "ProcessNode",
 	}
 
 	discoveredMethods := make(map[string]string)
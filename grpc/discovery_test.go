@@ -2,12 +2,16 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/lysfighting/ggRMCP/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 	grpcLib "google.golang.org/grpc"
 )
 
@@ -140,3 +144,40 @@ func TestServiceDiscoverer_InvokeMethodByTool(t *testing.T) {
 	// Verify all expectations were met
 	mockReflClient.AssertExpectations(t)
 }
+
+func TestServiceDiscoverer_DiscoverServicesNoServices(t *testing.T) {
+	newDiscoverer := func(logger *zap.Logger, strict bool, methods []types.MethodInfo, err error) *serviceDiscoverer {
+		discoverer := newServiceDiscovererWithConnManager(&mockConnectionManager{}, logger)
+		discoverer.strictDiscovery = strict
+
+		mockReflClient := &mockReflectionClient{}
+		mockReflClient.On("DiscoverMethods", mock.Anything).Return(methods, err)
+		discoverer.reflectionClient = mockReflClient
+		return discoverer
+	}
+
+	t.Run("lenient mode warns and exposes no tools", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		discoverer := newDiscoverer(zap.New(core), false, []types.MethodInfo{}, nil)
+
+		require.NoError(t, discoverer.DiscoverServices(context.Background()))
+		assert.Equal(t, 0, discoverer.GetMethodCount())
+		assert.Equal(t, 1, logs.FilterMessageSnippet("no services").Len())
+	})
+
+	t.Run("strict mode returns ErrNoServices", func(t *testing.T) {
+		discoverer := newDiscoverer(zap.NewNop(), true, []types.MethodInfo{}, nil)
+
+		err := discoverer.DiscoverServices(context.Background())
+		assert.ErrorIs(t, err, ErrNoServices)
+	})
+
+	t.Run("reflection failure is reported distinctly", func(t *testing.T) {
+		discoverer := newDiscoverer(zap.NewNop(), true, []types.MethodInfo{}, errors.New("reflection unavailable"))
+
+		err := discoverer.DiscoverServices(context.Background())
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNoServices)
+		assert.Contains(t, err.Error(), "reflection unavailable")
+	})
+}
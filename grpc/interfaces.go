@@ -46,6 +46,9 @@ type ServiceDiscoverer interface {
 	// HealthCheck performs a health check
 	HealthCheck(ctx context.Context) error
 
+	// Refresh reconnects and rediscovers services, recovering from a permanent failure
+	Refresh(ctx context.Context) error
+
 	// Close closes the service discoverer
 	Close() error
 
@@ -78,6 +81,10 @@ type ConnectionManagerConfig struct {
 	ConnectTimeout time.Duration   `json:"connect_timeout"`
 	KeepAlive      KeepAliveConfig `json:"keep_alive"`
 	MaxMessageSize int             `json:"max_message_size"`
+	Compression    string          `json:"compression"`
+
+	// Additional dial options, e.g. a custom dialer for in-memory connections
+	DialOptions []grpcLib.DialOption `json:"-"`
 }
 
 // KeepAliveConfig contains keep-alive settings for gRPC connections
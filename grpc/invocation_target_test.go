@@ -0,0 +1,213 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	grpcLib "google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// bufconnTargetConfig returns a connection config dialing the given in-memory listener
+func bufconnTargetConfig(listener *bufconn.Listener, host string) ConnectionManagerConfig {
+	return ConnectionManagerConfig{
+		Host:           host,
+		Port:           1,
+		ConnectTimeout: 5 * time.Second,
+		MaxMessageSize: 4 * 1024 * 1024,
+		DialOptions:    []grpcLib.DialOption{bufconnDialer(listener)},
+	}
+}
+
+// bufconnConnectionManager creates a connection manager dialing the given in-memory listener
+func bufconnConnectionManager(listener *bufconn.Listener, host string) ConnectionManager {
+	return NewConnectionManager(bufconnTargetConfig(listener, host), zap.NewNop())
+}
+
+func TestServiceDiscoverer_InvocationTargets(t *testing.T) {
+	fd := newStreamTestFile(t)
+
+	// Discovery goes through a server exposing reflection; invocation goes direct
+	discoveryListener := startBufconnListener(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "discovery")
+		reflection.Register(server)
+	})
+	invocationListener := startBufconnListener(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "invocation")
+	})
+
+	echo := countMethodInfo(fd, "Echo", false)
+	echo.ToolName = echo.GenerateToolName()
+
+	invoke := func(t *testing.T, targets map[string]ConnectionManagerConfig) string {
+		discoverer := newServiceDiscovererWithConnManager(bufconnConnectionManager(discoveryListener, "discovery"), zap.NewNop())
+		discoverer.invocationTargets = targets
+		t.Cleanup(func() { _ = discoverer.Close() })
+
+		require.NoError(t, discoverer.Connect(context.Background()))
+
+		tools := map[string]types.MethodInfo{echo.ToolName: echo}
+		discoverer.tools.Store(&tools)
+
+		result, err := discoverer.InvokeMethodByTool(context.Background(), nil, echo.ToolName, `{}`)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("service target overrides the discovery connection", func(t *testing.T) {
+		result := invoke(t, map[string]ConnectionManagerConfig{
+			"test.stream.CountService": bufconnTargetConfig(invocationListener, "invocation"),
+		})
+		assert.JSONEq(t, `{"label":"invocation"}`, result)
+	})
+
+	t.Run("wildcard target applies to all services", func(t *testing.T) {
+		result := invoke(t, map[string]ConnectionManagerConfig{
+			allServicesTarget: bufconnTargetConfig(invocationListener, "invocation"),
+		})
+		assert.JSONEq(t, `{"label":"invocation"}`, result)
+	})
+
+	t.Run("unmapped services use the discovery connection", func(t *testing.T) {
+		result := invoke(t, map[string]ConnectionManagerConfig{
+			"test.stream.OtherService": bufconnTargetConfig(invocationListener, "invocation"),
+		})
+		assert.JSONEq(t, `{"label":"discovery"}`, result)
+	})
+}
+
+func TestServiceDiscoverer_InvocationTargetsReconnect(t *testing.T) {
+	fd := newStreamTestFile(t)
+	discoveryListener := startBufconnListener(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "discovery")
+		reflection.Register(server)
+	})
+	invocationListener := startBufconnListener(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "invocation")
+	})
+
+	echo := countMethodInfo(fd, "Echo", false)
+	echo.ToolName = echo.GenerateToolName()
+
+	discoverer := newServiceDiscovererWithConnManager(bufconnConnectionManager(discoveryListener, "discovery"), zap.NewNop())
+	discoverer.invocationTargets = map[string]ConnectionManagerConfig{
+		"test.stream.CountService": bufconnTargetConfig(invocationListener, "invocation"),
+		allServicesTarget:          bufconnTargetConfig(invocationListener, "invocation"),
+	}
+	t.Cleanup(func() { _ = discoverer.Close() })
+	require.NoError(t, discoverer.Connect(context.Background()))
+
+	tools := map[string]types.MethodInfo{echo.ToolName: echo}
+	discoverer.tools.Store(&tools)
+	invoke := func() {
+		result, err := discoverer.InvokeMethodByTool(context.Background(), nil, echo.ToolName, `{}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"label":"invocation"}`, result)
+	}
+
+	// A successful reconnect swaps in new connections and closes the old ones
+	original := discoverer.invocation.conns["test.stream.CountService"]
+	require.NoError(t, discoverer.connectInvocationTargets(context.Background()))
+	assert.NotSame(t, original, discoverer.invocation.conns["test.stream.CountService"])
+	assert.Eventually(t, func() bool { return original.GetConnection() == nil }, 5*time.Second, 10*time.Millisecond)
+	invoke()
+
+	// A partial failure keeps every current connection in place
+	current := discoverer.invocation.conns
+	failing := bufconnTargetConfig(invocationListener, "invocation")
+	failing.Compression = "unregistered-compressor"
+	discoverer.invocationTargets[allServicesTarget] = failing
+
+	assert.Error(t, discoverer.connectInvocationTargets(context.Background()))
+	assert.Equal(t, current, discoverer.invocation.conns)
+	for _, connManager := range current {
+		assert.NotNil(t, connManager.GetConnection())
+	}
+	invoke()
+}
+
+// registerBlockingEcho registers a CountService whose Echo signals entered and then
+// waits for release before answering
+func registerBlockingEcho(server *grpcLib.Server, fd protoreflect.FileDescriptor, entered chan<- struct{}, release <-chan struct{}) {
+	requestDesc := fd.Messages().ByName("CountRequest")
+	responseDesc := fd.Messages().ByName("CountResponse")
+
+	server.RegisterService(&grpcLib.ServiceDesc{
+		ServiceName: "test.stream.CountService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpcLib.MethodDesc{
+			{
+				MethodName: "Echo",
+				Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpcLib.UnaryServerInterceptor) (interface{}, error) {
+					if err := dec(dynamicpb.NewMessage(requestDesc)); err != nil {
+						return nil, err
+					}
+					entered <- struct{}{}
+					<-release
+
+					response := dynamicpb.NewMessage(responseDesc)
+					response.Set(responseDesc.Fields().ByName("label"), protoreflect.ValueOfString("drained"))
+					return response, nil
+				},
+			},
+		},
+	}, struct{}{})
+}
+
+func TestServiceDiscoverer_InvocationTargetsDrainOnReconnect(t *testing.T) {
+	fd := newStreamTestFile(t)
+	discoveryListener := startBufconnListener(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "discovery")
+		reflection.Register(server)
+	})
+
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	invocationListener := startBufconnListener(t, func(server *grpcLib.Server) {
+		registerBlockingEcho(server, fd, entered, release)
+	})
+
+	echo := countMethodInfo(fd, "Echo", false)
+	echo.ToolName = echo.GenerateToolName()
+
+	discoverer := newServiceDiscovererWithConnManager(bufconnConnectionManager(discoveryListener, "discovery"), zap.NewNop())
+	discoverer.invocationTargets = map[string]ConnectionManagerConfig{
+		"test.stream.CountService": bufconnTargetConfig(invocationListener, "invocation"),
+	}
+	t.Cleanup(func() { _ = discoverer.Close() })
+	require.NoError(t, discoverer.Connect(context.Background()))
+
+	tools := map[string]types.MethodInfo{echo.ToolName: echo}
+	discoverer.tools.Store(&tools)
+
+	type invokeResult struct {
+		result string
+		err    error
+	}
+	done := make(chan invokeResult, 1)
+	go func() {
+		result, err := discoverer.InvokeMethodByTool(context.Background(), nil, echo.ToolName, `{}`)
+		done <- invokeResult{result, err}
+	}()
+	<-entered
+
+	// Reconnecting while the call is in flight keeps the old connection open until it finishes
+	original := discoverer.invocation.conns["test.stream.CountService"]
+	require.NoError(t, discoverer.connectInvocationTargets(context.Background()))
+	assert.NotNil(t, original.GetConnection())
+
+	close(release)
+	call := <-done
+	require.NoError(t, call.err)
+	assert.JSONEq(t, `{"label":"drained"}`, call.result)
+
+	assert.Eventually(t, func() bool { return original.GetConnection() == nil }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lysfighting/ggRMCP/types"
+	grpcLib "google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // Register the gzip compressor
+)
+
+// invokeOptionsKey is the context key for per-call invocation options
+type invokeOptionsKey struct{}
+
+// WithInvokeOptions returns a context carrying per-call invocation options
+func WithInvokeOptions(ctx context.Context, opts types.InvokeOptions) context.Context {
+	return context.WithValue(ctx, invokeOptionsKey{}, opts)
+}
+
+// InvokeOptionsFromContext returns the invocation options carried by the context
+func InvokeOptionsFromContext(ctx context.Context) types.InvokeOptions {
+	if opts, ok := ctx.Value(invokeOptionsKey{}).(types.InvokeOptions); ok {
+		return opts
+	}
+	return types.InvokeOptions{}
+}
+
+// callOptions converts invocation options into gRPC call options
+func callOptions(opts types.InvokeOptions) ([]grpcLib.CallOption, error) {
+	var callOpts []grpcLib.CallOption
+
+	if opts.Compression != "" {
+		if err := validateCompressor(opts.Compression); err != nil {
+			return nil, err
+		}
+		callOpts = append(callOpts, grpcLib.UseCompressor(opts.Compression))
+	}
+
+	return callOpts, nil
+}
+
+// validateCompressor checks that a compressor is registered under the given name
+func validateCompressor(name string) error {
+	if name == encoding.Identity {
+		return nil
+	}
+	if encoding.GetCompressor(name) == nil {
+		return fmt.Errorf("unsupported compression: %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	grpcLib "google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// flakyConnectionManager fails every Reconnect while failing is set
+type flakyConnectionManager struct {
+	ConnectionManager
+	failing    atomic.Bool
+	reconnects atomic.Int32
+}
+
+func (m *flakyConnectionManager) Reconnect(ctx context.Context) error {
+	m.reconnects.Add(1)
+	if m.failing.Load() {
+		return errors.New("connection refused")
+	}
+	return m.ConnectionManager.Reconnect(ctx)
+}
+
+func (m *flakyConnectionManager) HealthCheck(ctx context.Context) error {
+	if m.failing.Load() {
+		return errors.New("connection refused")
+	}
+	return m.ConnectionManager.HealthCheck(ctx)
+}
+
+func TestServiceDiscoverer_PermanentFailureAndRefresh(t *testing.T) {
+	listener := startBufconnListener(t, func(server *grpcLib.Server) {
+		reflection.Register(server)
+	})
+
+	connManager := &flakyConnectionManager{ConnectionManager: bufconnConnectionManager(listener, "reconnect")}
+	discoverer := newServiceDiscovererWithConnManager(connManager, zap.NewNop())
+	discoverer.reconnectInterval = 0
+	discoverer.maxReconnectAttempts = 3
+	t.Cleanup(func() { _ = discoverer.Close() })
+
+	require.NoError(t, discoverer.Connect(context.Background()))
+
+	// Exhausting the attempts enters the permanent failure state
+	connManager.failing.Store(true)
+	err := discoverer.Reconnect(context.Background())
+	require.ErrorIs(t, err, ErrPermanentFailure)
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Equal(t, int32(3), connManager.reconnects.Load())
+
+	stats := discoverer.GetServiceStats()
+	assert.Equal(t, true, stats["permanentFailure"])
+	assert.Equal(t, int64(1), stats["permanentFailureCount"])
+	assert.Equal(t, false, stats["isConnected"])
+
+	// Further reconnects and calls fail fast without touching the connection
+	connManager.failing.Store(false)
+	assert.ErrorIs(t, discoverer.Reconnect(context.Background()), ErrPermanentFailure)
+	assert.Equal(t, int32(3), connManager.reconnects.Load())
+
+	tools := map[string]types.MethodInfo{"test_service_method": {Name: "Method", ToolName: "test_service_method"}}
+	discoverer.tools.Store(&tools)
+	_, err = discoverer.InvokeMethodByTool(context.Background(), nil, "test_service_method", `{}`)
+	assert.ErrorIs(t, err, ErrPermanentFailure)
+	assert.ErrorIs(t, discoverer.HealthCheck(context.Background()), ErrPermanentFailure)
+
+	// A failed refresh keeps the state
+	connManager.failing.Store(true)
+	assert.Error(t, discoverer.Refresh(context.Background()))
+	assert.Equal(t, true, discoverer.GetServiceStats()["permanentFailure"])
+
+	// A successful refresh recovers while keeping the failure count
+	connManager.failing.Store(false)
+	require.NoError(t, discoverer.Refresh(context.Background()))
+
+	stats = discoverer.GetServiceStats()
+	assert.Equal(t, false, stats["permanentFailure"])
+	assert.Equal(t, int64(1), stats["permanentFailureCount"])
+	assert.NotContains(t, stats, "lastFailure")
+	assert.NoError(t, discoverer.HealthCheck(context.Background()))
+	assert.NoError(t, discoverer.Reconnect(context.Background()))
+}
+
+func TestServiceDiscoverer_HealthMonitorEntersPermanentFailure(t *testing.T) {
+	listener := startBufconnListener(t, func(server *grpcLib.Server) {
+		reflection.Register(server)
+	})
+
+	connManager := &flakyConnectionManager{ConnectionManager: bufconnConnectionManager(listener, "monitor")}
+	discoverer := newServiceDiscovererWithConnManager(connManager, zap.NewNop())
+	discoverer.reconnectInterval = 0
+	discoverer.maxReconnectAttempts = 2
+	discoverer.healthCheckInterval = 10 * time.Millisecond
+	t.Cleanup(func() { _ = discoverer.Close() })
+
+	require.NoError(t, discoverer.Connect(context.Background()))
+
+	// A failing health check triggers reconnects until the attempts are exhausted
+	connManager.failing.Store(true)
+	assert.Eventually(t, func() bool {
+		return errors.Is(discoverer.HealthCheck(context.Background()), ErrPermanentFailure)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), connManager.reconnects.Load())
+
+	// The monitor leaves recovery to an admin refresh
+	connManager.failing.Store(false)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(2), connManager.reconnects.Load())
+
+	require.NoError(t, discoverer.Refresh(context.Background()))
+	assert.NoError(t, discoverer.HealthCheck(context.Background()))
+}
+
+func TestServiceDiscoverer_RefreshDuringInvoke(t *testing.T) {
+	fd := newStreamTestFile(t)
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(fd))
+
+	// Reflection serves the test file so every refresh rediscovers the same tools
+	listener := startBufconnListener(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "refresh")
+		grpc_reflection_v1alpha.RegisterServerReflectionServer(server, reflection.NewServer(reflection.ServerOptions{
+			Services:           server,
+			DescriptorResolver: files,
+		}))
+	})
+
+	discoverer := newServiceDiscovererWithConnManager(bufconnConnectionManager(listener, "refresh"), zap.NewNop())
+	t.Cleanup(func() { _ = discoverer.Close() })
+	require.NoError(t, discoverer.Connect(context.Background()))
+	require.NoError(t, discoverer.DiscoverServices(context.Background()))
+
+	echo := countMethodInfo(fd, "Echo", false)
+	echo.ToolName = echo.GenerateToolName()
+
+	// Invocations racing a refresh may fail on the replaced connection but must not race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, _ = discoverer.InvokeMethodByTool(context.Background(), nil, echo.ToolName, `{}`)
+				_ = discoverer.GetServiceStats()
+			}
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, discoverer.Refresh(context.Background()))
+	}
+	wg.Wait()
+
+	result, err := discoverer.InvokeMethodByTool(context.Background(), nil, echo.ToolName, `{}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"label":"refresh"}`, result)
+}
+
+func TestServiceDiscoverer_HealthMonitorDisabledByDefault(t *testing.T) {
+	discoverer, err := NewServiceDiscovererWithConfig(zap.NewNop(), config.Default().GRPC)
+	require.NoError(t, err)
+
+	d := discoverer.(*serviceDiscoverer)
+	d.startHealthMonitor()
+	assert.Nil(t, d.stopMonitor)
+}
@@ -2,11 +2,14 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/lysfighting/ggRMCP/config"
 	"github.com/lysfighting/ggRMCP/types"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -30,15 +33,26 @@ type reflectionClient struct {
 	// Cache for resolved file descriptors
 	fdCache map[string]*descriptorpb.FileDescriptorProto
 	mu      sync.RWMutex
+
+	// Limits on aggregated server-streaming results (0 = unlimited)
+	maxStreamMessages int
+	maxStreamBytes    int64
 }
 
 // NewReflectionClient creates a new reflection client
 func NewReflectionClient(conn *grpc.ClientConn, logger *zap.Logger) ReflectionClient {
+	return newReflectionClient(conn, logger, config.Default().GRPC.ServerStreaming)
+}
+
+// newReflectionClient creates a reflection client with the given server-streaming limits
+func newReflectionClient(conn *grpc.ClientConn, logger *zap.Logger, streaming config.ServerStreamingConfig) *reflectionClient {
 	return &reflectionClient{
-		conn:    conn,
-		client:  grpc_reflection_v1alpha.NewServerReflectionClient(conn),
-		logger:  logger,
-		fdCache: make(map[string]*descriptorpb.FileDescriptorProto),
+		conn:              conn,
+		client:            grpc_reflection_v1alpha.NewServerReflectionClient(conn),
+		logger:            logger,
+		fdCache:           make(map[string]*descriptorpb.FileDescriptorProto),
+		maxStreamMessages: streaming.MaxMessages,
+		maxStreamBytes:    streaming.MaxBytes,
 	}
 }
 
@@ -63,6 +77,12 @@ func (r *reflectionClient) DiscoverMethods(ctx context.Context) ([]types.MethodI
 		zap.Strings("originalServices", serviceNames),
 		zap.Strings("filteredServices", filteredServices))
 
+	if len(filteredServices) == 0 {
+		r.logger.Warn("gRPC reflection returned no user services",
+			zap.Strings("services", serviceNames))
+		return []types.MethodInfo{}, nil
+	}
+
 	// Group services by file descriptor to avoid redundant lookups
 	fileDescriptorMap := make(map[string]*descriptorpb.FileDescriptorProto)
 	serviceToFileMap := make(map[string]string)
@@ -260,6 +280,8 @@ func (r *reflectionClient) createMethodInfoWithServiceContext(ctx context.Contex
 		Name:              method.GetName(),
 		FullName:          fmt.Sprintf("%s.%s", serviceName, method.GetName()),
 		ServiceName:       serviceName,
+		PackageName:       fileDescriptor.GetPackage(),
+		FileName:          fileDescriptor.GetName(),
 		InputType:         method.GetInputType(),
 		OutputType:        method.GetOutputType(),
 		IsClientStreaming: method.GetClientStreaming(),
@@ -341,11 +363,12 @@ func (r *reflectionClient) InvokeMethod(ctx context.Context, headers map[string]
 			zap.Int("headerCount", len(headers)))
 	}
 
+	// Only the input size is logged; the handler logs the arguments after redaction
 	r.logger.Debug("Starting dynamic method invocation",
 		zap.String("method", method.FullName),
 		zap.String("inputType", string(method.InputDescriptor.FullName())),
 		zap.String("outputType", string(method.OutputDescriptor.FullName())),
-		zap.String("inputJSON", inputJSON))
+		zap.Int("inputSize", len(inputJSON)))
 
 	// 1. Create dynamic input message
 	inputMsg := dynamicpb.NewMessage(method.InputDescriptor)
@@ -357,7 +380,7 @@ func (r *reflectionClient) InvokeMethod(ctx context.Context, headers map[string]
 		}
 	}
 
-	r.logger.Debug("Created input message", zap.String("message", inputMsg.String()))
+	r.logger.Debug("Created input message", zap.String("messageType", string(inputMsg.Descriptor().FullName())))
 
 	// 3. Create dynamic output message
 	outputMsg := dynamicpb.NewMessage(method.OutputDescriptor)
@@ -370,7 +393,18 @@ func (r *reflectionClient) InvokeMethod(ctx context.Context, headers map[string]
 		zap.String("grpcMethodName", grpcMethodName),
 		zap.String("originalFullName", method.FullName))
 
-	err := r.conn.Invoke(ctx, grpcMethodName, inputMsg, outputMsg)
+	// Apply per-call options such as compression
+	callOpts, err := callOptions(InvokeOptionsFromContext(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	// Server-streaming methods are aggregated into a single result
+	if method.IsServerStreaming {
+		return r.invokeServerStreaming(ctx, grpcMethodName, method, inputMsg, callOpts...)
+	}
+
+	err = r.conn.Invoke(ctx, grpcMethodName, inputMsg, outputMsg, callOpts...)
 	if err != nil {
 		return "", fmt.Errorf("gRPC call failed: %w", err)
 	}
@@ -390,6 +424,84 @@ func (r *reflectionClient) InvokeMethod(ctx context.Context, headers map[string]
 	return string(outputJSON), nil
 }
 
+// ErrStreamLimitExceeded is returned when a server stream exceeds the aggregation limits
+var ErrStreamLimitExceeded = errors.New("server stream exceeded aggregation limit")
+
+// invokeServerStreaming invokes a server-streaming method and aggregates all streamed
+// responses into one result encoded in the response format selected for the call
+func (r *reflectionClient) invokeServerStreaming(ctx context.Context, grpcMethodName string, method MethodInfo, inputMsg *dynamicpb.Message, callOpts ...grpc.CallOption) (string, error) {
+	streamDesc := &grpc.StreamDesc{
+		StreamName:    method.Name,
+		ServerStreams: true,
+	}
+
+	// Cancelling tears the stream down when a limit is hit before the server finishes
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := r.conn.NewStream(streamCtx, streamDesc, grpcMethodName, callOpts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to open gRPC stream: %w", err)
+	}
+
+	if err := stream.SendMsg(inputMsg); err != nil {
+		return "", fmt.Errorf("failed to send request on gRPC stream: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return "", fmt.Errorf("failed to close gRPC stream: %w", err)
+	}
+
+	// Collect every streamed message as JSON, up to the configured limits
+	var messages []string
+	var totalBytes int64
+	for {
+		outputMsg := dynamicpb.NewMessage(method.OutputDescriptor)
+		if err := stream.RecvMsg(outputMsg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", fmt.Errorf("gRPC stream failed: %w", err)
+		}
+
+		outputJSON, err := protojson.Marshal(outputMsg)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal output to JSON: %w", err)
+		}
+
+		if r.maxStreamMessages > 0 && len(messages) >= r.maxStreamMessages {
+			return "", fmt.Errorf("%w: more than %d messages", ErrStreamLimitExceeded, r.maxStreamMessages)
+		}
+		totalBytes += int64(len(outputJSON))
+		if r.maxStreamBytes > 0 && totalBytes > r.maxStreamBytes {
+			return "", fmt.Errorf("%w: more than %d bytes", ErrStreamLimitExceeded, r.maxStreamBytes)
+		}
+		messages = append(messages, string(outputJSON))
+	}
+
+	format := InvokeOptionsFromContext(ctx).ResponseFormat
+
+	r.logger.Debug("Server-streaming invocation successful",
+		zap.String("method", method.FullName),
+		zap.Int("messageCount", len(messages)),
+		zap.String("responseFormat", string(format)))
+
+	return formatStreamedMessages(messages, format), nil
+}
+
+// formatStreamedMessages encodes streamed JSON messages as a JSON array or as NDJSON lines
+func formatStreamedMessages(messages []string, format types.ResponseFormat) string {
+	if format == types.ResponseFormatNDJSON {
+		var builder strings.Builder
+		for _, message := range messages {
+			builder.WriteString(message)
+			builder.WriteString("\n")
+		}
+		return builder.String()
+	}
+
+	return "[" + strings.Join(messages, ",") + "]"
+}
+
 // filterInternalServices filters out internal gRPC services
 func (r *reflectionClient) filterInternalServices(services []string) []string {
 	var filtered []string
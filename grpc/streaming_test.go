@@ -0,0 +1,266 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	grpcLib "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// newStreamTestFile builds the descriptors used by the in-memory streaming service
+func newStreamTestFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("stream_test.proto"),
+		Package: proto.String("test.stream"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("CountRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:     proto.String("count"),
+						JsonName: proto.String("count"),
+						Number:   proto.Int32(1),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+					},
+				},
+			},
+			{
+				Name: proto.String("CountResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:     proto.String("label"),
+						JsonName: proto.String("label"),
+						Number:   proto.Int32(1),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					},
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("CountService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       proto.String("Echo"),
+						InputType:  proto.String(".test.stream.CountRequest"),
+						OutputType: proto.String(".test.stream.CountResponse"),
+					},
+					{
+						Name:            proto.String("Count"),
+						InputType:       proto.String(".test.stream.CountRequest"),
+						OutputType:      proto.String(".test.stream.CountResponse"),
+						ServerStreaming: proto.Bool(true),
+					},
+				},
+			},
+		},
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	return fd
+}
+
+// registerCountService registers a service whose Count method streams one message per
+// requested count and whose Echo method returns a single labelled message
+func registerCountService(server *grpcLib.Server, fd protoreflect.FileDescriptor, label string) {
+	requestDesc := fd.Messages().ByName("CountRequest")
+	responseDesc := fd.Messages().ByName("CountResponse")
+	labelField := responseDesc.Fields().ByName("label")
+
+	server.RegisterService(&grpcLib.ServiceDesc{
+		ServiceName: "test.stream.CountService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpcLib.MethodDesc{
+			{
+				MethodName: "Echo",
+				Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpcLib.UnaryServerInterceptor) (interface{}, error) {
+					request := dynamicpb.NewMessage(requestDesc)
+					if err := dec(request); err != nil {
+						return nil, err
+					}
+					response := dynamicpb.NewMessage(responseDesc)
+					response.Set(labelField, protoreflect.ValueOfString(label))
+					return response, nil
+				},
+			},
+		},
+		Streams: []grpcLib.StreamDesc{
+			{
+				StreamName:    "Count",
+				ServerStreams: true,
+				Handler: func(_ interface{}, stream grpcLib.ServerStream) error {
+					request := dynamicpb.NewMessage(requestDesc)
+					if err := stream.RecvMsg(request); err != nil {
+						return err
+					}
+
+					count := request.Get(requestDesc.Fields().ByName("count")).Int()
+					for i := int64(1); i <= count; i++ {
+						response := dynamicpb.NewMessage(responseDesc)
+						response.Set(labelField, protoreflect.ValueOfString(fmt.Sprintf("%s-%d", label, i)))
+						if err := stream.SendMsg(response); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+		},
+	}, struct{}{})
+}
+
+// startBufconnListener starts an in-memory gRPC server and returns its listener
+func startBufconnListener(t *testing.T, register func(*grpcLib.Server), opts ...grpcLib.ServerOption) *bufconn.Listener {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpcLib.NewServer(opts...)
+	register(server)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener
+}
+
+// bufconnDialer returns a dial option connecting to the given in-memory listener
+func bufconnDialer(listener *bufconn.Listener) grpcLib.DialOption {
+	return grpcLib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	})
+}
+
+// startBufconnServer starts an in-memory gRPC server and returns a client connection to it
+func startBufconnServer(t *testing.T, register func(*grpcLib.Server), opts ...grpcLib.ServerOption) *grpcLib.ClientConn {
+	t.Helper()
+
+	listener := startBufconnListener(t, register, opts...)
+
+	conn, err := grpcLib.NewClient("passthrough:///bufnet",
+		bufconnDialer(listener),
+		grpcLib.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// countMethodInfo returns method info for one of the CountService methods
+func countMethodInfo(fd protoreflect.FileDescriptor, name string, serverStreaming bool) types.MethodInfo {
+	return types.MethodInfo{
+		Name:              name,
+		FullName:          "test.stream.CountService." + name,
+		ServiceName:       "test.stream.CountService",
+		InputDescriptor:   fd.Messages().ByName("CountRequest"),
+		OutputDescriptor:  fd.Messages().ByName("CountResponse"),
+		IsServerStreaming: serverStreaming,
+	}
+}
+
+func TestInvokeMethod_ServerStreamingNDJSON(t *testing.T) {
+	fd := newStreamTestFile(t)
+	conn := startBufconnServer(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "message")
+	})
+
+	client := &reflectionClient{
+		conn:    conn,
+		logger:  zap.NewNop(),
+		fdCache: make(map[string]*descriptorpb.FileDescriptorProto),
+	}
+	method := countMethodInfo(fd, "Count", true)
+
+	ctx := WithInvokeOptions(context.Background(), types.InvokeOptions{
+		ResponseFormat: types.ResponseFormatNDJSON,
+	})
+	result, err := client.InvokeMethod(ctx, nil, method, `{"count":3}`)
+	require.NoError(t, err)
+
+	// Each streamed message is its own JSON line
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		var message map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &message), "line %d should be valid JSON", i)
+		assert.Equal(t, fmt.Sprintf("message-%d", i+1), message["label"])
+	}
+
+	// The default encoding aggregates the same messages into a JSON array
+	result, err = client.InvokeMethod(context.Background(), nil, method, `{"count":3}`)
+	require.NoError(t, err)
+
+	var messages []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result), &messages))
+	require.Len(t, messages, 3)
+	assert.Equal(t, "message-3", messages[2]["label"])
+}
+
+func TestInvokeMethod_ServerStreamingLimits(t *testing.T) {
+	fd := newStreamTestFile(t)
+	conn := startBufconnServer(t, func(server *grpcLib.Server) {
+		registerCountService(server, fd, "message")
+	})
+	method := countMethodInfo(fd, "Count", true)
+
+	t.Run("message limit", func(t *testing.T) {
+		client := newReflectionClient(conn, zap.NewNop(), config.ServerStreamingConfig{Enabled: true, MaxMessages: 2})
+
+		_, err := client.InvokeMethod(context.Background(), nil, method, `{"count":3}`)
+		assert.ErrorIs(t, err, ErrStreamLimitExceeded)
+
+		result, err := client.InvokeMethod(context.Background(), nil, method, `{"count":2}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"label":"message-1"},{"label":"message-2"}]`, result)
+	})
+
+	t.Run("byte limit", func(t *testing.T) {
+		// Each message is {"label":"message-N"}, 21 bytes
+		client := newReflectionClient(conn, zap.NewNop(), config.ServerStreamingConfig{Enabled: true, MaxBytes: 50})
+
+		_, err := client.InvokeMethod(context.Background(), nil, method, `{"count":3}`)
+		assert.ErrorIs(t, err, ErrStreamLimitExceeded)
+
+		_, err = client.InvokeMethod(context.Background(), nil, method, `{"count":2}`)
+		assert.NoError(t, err)
+	})
+}
+
+func TestServiceDiscoverer_ServerStreamingDisabled(t *testing.T) {
+	fd := newStreamTestFile(t)
+	method := countMethodInfo(fd, "Count", true)
+	method.ToolName = method.GenerateToolName()
+
+	discoverer := newServiceDiscovererWithConnManager(&mockConnectionManager{}, zap.NewNop())
+	discoverer.reflectionClient = &mockReflectionClient{}
+	tools := map[string]types.MethodInfo{method.ToolName: method}
+	discoverer.tools.Store(&tools)
+
+	_, err := discoverer.InvokeMethodByTool(context.Background(), nil, method.ToolName, `{"count":1}`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not enabled")
+}
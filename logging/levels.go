@@ -0,0 +1,96 @@
+// Package logging provides logger helpers shared by the gateway subsystems.
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levelOverrideCore filters entries by a per-logger minimum level
+type levelOverrideCore struct {
+	zapcore.Core
+	defaultLevel zapcore.Level
+	overrides    map[string]zapcore.Level
+}
+
+// NewLevelOverrideCore wraps a core so that entries from named loggers (e.g. "discovery")
+// are filtered by their own level, falling back to defaultLevel for all other loggers.
+// The wrapped core must itself be enabled at MinLevel(defaultLevel, overrides).
+func NewLevelOverrideCore(core zapcore.Core, defaultLevel zapcore.Level, overrides map[string]zapcore.Level) zapcore.Core {
+	if len(overrides) == 0 {
+		return core
+	}
+
+	return &levelOverrideCore{
+		Core:         core,
+		defaultLevel: defaultLevel,
+		overrides:    overrides,
+	}
+}
+
+// ParseLevels converts per-logger level names into zap levels
+func ParseLevels(levels map[string]string) (map[string]zapcore.Level, error) {
+	parsed := make(map[string]zapcore.Level, len(levels))
+	for name, text := range levels {
+		level, err := zapcore.ParseLevel(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q for logger %s: %w", text, name, err)
+		}
+		parsed[name] = level
+	}
+	return parsed, nil
+}
+
+// MinLevel returns the most verbose level among the default and the overrides
+func MinLevel(defaultLevel zapcore.Level, overrides map[string]zapcore.Level) zapcore.Level {
+	minLevel := defaultLevel
+	for _, level := range overrides {
+		if level < minLevel {
+			minLevel = level
+		}
+	}
+	return minLevel
+}
+
+// Enabled reports whether any logger could emit entries at the given level
+func (c *levelOverrideCore) Enabled(level zapcore.Level) bool {
+	return MinLevel(c.defaultLevel, c.overrides).Enabled(level) && c.Core.Enabled(level)
+}
+
+// With adds structured context while keeping the per-logger levels
+func (c *levelOverrideCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelOverrideCore{
+		Core:         c.Core.With(fields),
+		defaultLevel: c.defaultLevel,
+		overrides:    c.overrides,
+	}
+}
+
+// Check filters the entry using the level configured for its logger
+func (c *levelOverrideCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levelFor(entry.LoggerName).Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// levelFor returns the level for a logger name, matching the longest configured
+// prefix so that an override for "discovery" also applies to "discovery.reflection"
+func (c *levelOverrideCore) levelFor(loggerName string) zapcore.Level {
+	name := loggerName
+	for name != "" {
+		if level, exists := c.overrides[name]; exists {
+			return level
+		}
+
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			break
+		}
+		name = name[:idx]
+	}
+
+	return c.defaultLevel
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevelOverrideCore_PerLoggerLevels(t *testing.T) {
+	overrides, err := ParseLevels(map[string]string{
+		"discovery": "debug",
+		"session":   "warn",
+	})
+	require.NoError(t, err)
+
+	observedCore, logs := observer.New(MinLevel(zapcore.InfoLevel, overrides))
+	logger := zap.New(NewLevelOverrideCore(observedCore, zapcore.InfoLevel, overrides))
+
+	logger.Named("discovery").Debug("discovery debug")
+	logger.Named("discovery").Named("reflection").Debug("nested discovery debug")
+	logger.Named("session").Info("session info")
+	logger.Named("session").Warn("session warn")
+	logger.Named("connection").Debug("connection debug")
+	logger.Named("connection").Info("connection info")
+	logger.Debug("root debug")
+
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+
+	assert.Equal(t, []string{
+		"discovery debug",
+		"nested discovery debug",
+		"session warn",
+		"connection info",
+	}, messages)
+}
+
+func TestParseLevels_Invalid(t *testing.T) {
+	_, err := ParseLevels(map[string]string{"discovery": "verbose"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package logging
+
+import "strings"
+
+// RedactedValue replaces masked argument values in logs
+const RedactedValue = "[REDACTED]"
+
+// AllTools is the redaction rule key applied to every tool
+const AllTools = "*"
+
+// Redactor masks configured argument paths per tool before they are logged
+type Redactor struct {
+	rules map[string][][]string
+}
+
+// NewRedactor creates a redactor from rules mapping tool names to dotted argument
+// paths (e.g. "credentials.password"); rules under "*" apply to every tool
+func NewRedactor(rules map[string][]string) *Redactor {
+	r := &Redactor{rules: make(map[string][][]string, len(rules))}
+	for tool, paths := range rules {
+		for _, path := range paths {
+			if path == "" {
+				continue
+			}
+			r.rules[tool] = append(r.rules[tool], strings.Split(path, "."))
+		}
+	}
+	return r
+}
+
+// Redact returns a copy of args with the tool's configured paths masked.
+// Arrays along a path are traversed so every element is masked.
+func (r *Redactor) Redact(toolName string, args interface{}) interface{} {
+	var paths [][]string
+	paths = append(paths, r.rules[AllTools]...)
+	paths = append(paths, r.rules[toolName]...)
+	if len(paths) == 0 {
+		return args
+	}
+
+	redacted := copyValue(args)
+	for _, path := range paths {
+		redacted = redactPath(redacted, path)
+	}
+	return redacted
+}
+
+// redactPath masks the value at path within v
+func redactPath(v interface{}, path []string) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		child, exists := node[path[0]]
+		if !exists {
+			return node
+		}
+		if len(path) == 1 {
+			node[path[0]] = RedactedValue
+		} else {
+			node[path[0]] = redactPath(child, path[1:])
+		}
+		return node
+	case []interface{}:
+		for i := range node {
+			node[i] = redactPath(node[i], path)
+		}
+		return node
+	default:
+		return v
+	}
+}
+
+// copyValue deep-copies decoded JSON maps and arrays so redaction never mutates the request
+func copyValue(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			copied[key] = copyValue(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(node))
+		for i, value := range node {
+			copied[i] = copyValue(value)
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -37,6 +37,12 @@ func (r RequestID) String() string {
 	return fmt.Sprintf("%v", r.Value)
 }
 
+// Key returns a type-qualified key for the RequestID so that the string "1"
+// and the number 1 are treated as distinct identifiers
+func (r RequestID) Key() string {
+	return fmt.Sprintf("%T:%v", r.Value, r.Value)
+}
+
 // JSONRPCRequest represents a JSON-RPC 2.0 request
 type JSONRPCRequest struct {
 	JSONRPC string                 `json:"jsonrpc"`
@@ -74,6 +80,15 @@ const (
 	ErrorCodeInternalError  = -32603
 )
 
+// Implementation-defined error codes
+const (
+	// ErrorCodeUnauthenticated signals that the client should refresh its credentials and retry
+	ErrorCodeUnauthenticated = -32001
+
+	// ErrorCodeRateLimited signals that the session exceeded its request rate limit
+	ErrorCodeRateLimited = -32002
+)
+
 // ServerInfo represents the server information
 type ServerInfo struct {
 	Name    string `json:"name"`
@@ -91,6 +106,9 @@ type ServerCapabilities struct {
 	Tools     *ToolsCapability     `json:"tools,omitempty"`
 	Prompts   *PromptsCapability   `json:"prompts,omitempty"`
 	Resources *ResourcesCapability `json:"resources,omitempty"`
+
+	// Experimental holds non-standard capabilities keyed by name
+	Experimental map[string]interface{} `json:"experimental,omitempty"`
 }
 
 // ToolsCapability represents tools capability
@@ -113,6 +131,7 @@ type InitializationResult struct {
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ServerCapabilities `json:"capabilities"`
 	ServerInfo      ServerInfo         `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
 }
 
 // ContentType represents different content types
@@ -238,6 +257,11 @@ type ResourceContents struct {
 	Blob     string `json:"blob,omitempty"`
 }
 
+// ResourceReadResult represents the result of reading a resource
+type ResourceReadResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
 // ResourceLink represents a resource link
 type ResourceLink struct {
 	URI         string `json:"uri"`
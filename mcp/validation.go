@@ -4,20 +4,50 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/types"
 )
 
+// jsonRPCVersion is the JSON-RPC version spoken by the gateway
+const jsonRPCVersion = "2.0"
+
+// lenientJSONRPCVersions are the version values tolerated in lenient mode
+var lenientJSONRPCVersions = []string{jsonRPCVersion, "2", ""}
+
 // Validator provides validation functionality
 type Validator struct {
 	maxFieldLength int
 	maxToolName    int
+
+	// JSON-RPC versions accepted and normalized to "2.0"
+	allowedVersions map[string]bool
 }
 
 // NewValidator creates a new validator with default settings
 func NewValidator() *Validator {
 	return &Validator{
-		maxFieldLength: 1024,
-		maxToolName:    128,
+		maxFieldLength:  1024,
+		maxToolName:     128,
+		allowedVersions: map[string]bool{jsonRPCVersion: true},
+	}
+}
+
+// NewValidatorWithConfig creates a new validator from validation configuration
+func NewValidatorWithConfig(cfg config.ValidationConfig) *Validator {
+	v := NewValidator()
+	if cfg.MaxFieldLength > 0 {
+		v.maxFieldLength = cfg.MaxFieldLength
+	}
+	if cfg.MaxToolNameLength > 0 {
+		v.maxToolName = cfg.MaxToolNameLength
 	}
+	if cfg.LenientJSONRPCVersion {
+		for _, version := range lenientJSONRPCVersions {
+			v.allowedVersions[version] = true
+		}
+	}
+	return v
 }
 
 // ValidateRequest validates a JSON-RPC request
@@ -25,7 +55,7 @@ func (v *Validator) ValidateRequest(req *JSONRPCRequest) error {
 	var errors ValidationErrors
 
 	// Validate JSON-RPC version
-	if req.JSONRPC != "2.0" {
+	if !v.allowedVersions[req.JSONRPC] {
 		errors.Add("jsonrpc", "must be '2.0'")
 	}
 
@@ -110,6 +140,24 @@ func (v *Validator) ValidateToolCallParams(params map[string]interface{}) error
 		errors.Add("name", "contains invalid characters")
 	}
 
+	// Validate response format if present
+	if format, exists := params["responseFormat"]; exists {
+		if formatStr, ok := format.(string); !ok {
+			errors.Add("responseFormat", "must be a string")
+		} else if !types.ResponseFormat(formatStr).IsValid() {
+			errors.Add("responseFormat", fmt.Sprintf("must be '%s' or '%s'", types.ResponseFormatJSON, types.ResponseFormatNDJSON))
+		}
+	}
+
+	// Validate compression if present; the compressor itself is checked on invocation
+	if compression, exists := params["compression"]; exists {
+		if compressionStr, ok := compression.(string); !ok {
+			errors.Add("compression", "must be a string")
+		} else if compressionStr == "" || len(compressionStr) > v.maxFieldLength {
+			errors.Add("compression", "must be a non-empty compressor name")
+		}
+	}
+
 	// Validate arguments if present
 	if args, exists := params["arguments"]; exists {
 		if err := v.validateArguments(args); err != nil {
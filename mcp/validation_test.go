@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequest_JSONRPCVersion(t *testing.T) {
+	lenientConfig := config.Default().MCP.Validation
+	lenientConfig.LenientJSONRPCVersion = true
+
+	tests := []struct {
+		name      string
+		validator *Validator
+		version   string
+		wantErr   bool
+	}{
+		{name: "strict accepts 2.0", validator: NewValidator(), version: "2.0"},
+		{name: "strict rejects 2", validator: NewValidator(), version: "2", wantErr: true},
+		{name: "strict rejects missing", validator: NewValidator(), version: "", wantErr: true},
+		{name: "lenient accepts 2", validator: NewValidatorWithConfig(lenientConfig), version: "2"},
+		{name: "lenient accepts missing", validator: NewValidatorWithConfig(lenientConfig), version: ""},
+		{name: "lenient rejects 1.0", validator: NewValidatorWithConfig(lenientConfig), version: "1.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &JSONRPCRequest{
+				JSONRPC: tt.version,
+				ID:      RequestID{Value: 1},
+				Method:  "tools/list",
+			}
+
+			err := tt.validator.ValidateRequest(req)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "must be '2.0'")
+				return
+			}
+
+			require.NoError(t, err)
+			// Validation never rewrites the request
+			assert.Equal(t, tt.version, req.JSONRPC)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package ggRMCP
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,11 +13,13 @@ import (
 	"github.com/gorilla/mux"
 	appconfig "github.com/lysfighting/ggRMCP/config"
 	"github.com/lysfighting/ggRMCP/grpc"
+	"github.com/lysfighting/ggRMCP/logging"
 	"github.com/lysfighting/ggRMCP/server"
 	"github.com/lysfighting/ggRMCP/session"
 	"github.com/lysfighting/ggRMCP/tools"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/net/netutil"
 )
 
 // Config holds application configuration
@@ -27,6 +30,34 @@ type Config struct {
 	LogLevel       string
 	Development    bool
 	DescriptorPath string
+
+	// Settings holds the full application configuration; defaults are used when nil
+	Settings *appconfig.Config
+}
+
+// settings returns the application configuration, falling back to defaults
+func (c *Config) settings() *appconfig.Config {
+	if c.Settings != nil {
+		return c.Settings
+	}
+	return appconfig.Default()
+}
+
+// grpcSettings returns the gRPC configuration with the target and descriptor set
+// taken from the command-line config
+func grpcSettings(config *Config, settings *appconfig.Config) appconfig.GRPCConfig {
+	descriptorConfig := settings.GRPC.DescriptorSet
+	descriptorConfig.Enabled = config.DescriptorPath != ""
+	descriptorConfig.Path = config.DescriptorPath
+	descriptorConfig.PreferOverReflection = false // Use reflection as primary, descriptor as enhancement
+	descriptorConfig.IncludeSourceInfo = true
+
+	grpcConfig := settings.GRPC
+	grpcConfig.Host = config.GRPCHost
+	grpcConfig.Port = config.GRPCPort
+	grpcConfig.DescriptorSet = descriptorConfig
+
+	return grpcConfig
 }
 
 // setupLogger creates a configured logger
@@ -41,20 +72,30 @@ func setupLogger(config *Config) (*zap.Logger, error) {
 	}
 
 	// Set log level
+	var level zapcore.Level
 	switch config.LogLevel {
 	case "debug":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+		level = zap.DebugLevel
 	case "info":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+		level = zap.InfoLevel
 	case "warn":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+		level = zap.WarnLevel
 	case "error":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+		level = zap.ErrorLevel
 	default:
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+		level = zap.InfoLevel
 	}
 
-	return zapConfig.Build()
+	// Apply per-subsystem level overrides on top of the global level
+	overrides, err := logging.ParseLevels(config.settings().Logging.Levels)
+	if err != nil {
+		return nil, err
+	}
+	zapConfig.Level = zap.NewAtomicLevelAt(logging.MinLevel(level, overrides))
+
+	return zapConfig.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return logging.NewLevelOverrideCore(core, level, overrides)
+	}))
 }
 
 // setupRouter creates the HTTP router with all routes
@@ -70,11 +111,40 @@ func setupRouter(handler *server.Handler) *mux.Router {
 	// Metrics endpoint
 	router.HandleFunc("/metrics", handler.MetricsHandler).Methods("GET")
 
+	// Debug endpoints
+	router.HandleFunc("/debug/streaming-methods", handler.StreamingMethodsHandler).Methods("GET")
+
 	return router
 }
 
-// gracefulShutdown handles graceful shutdown of the HTTP server
-func gracefulShutdown(server *http.Server, logger *zap.Logger) {
+// setupAdminRouter creates the HTTP router for the admin listener
+func setupAdminRouter(handler *server.Handler) *mux.Router {
+	router := mux.NewRouter()
+
+	router.HandleFunc("/admin/sessions", handler.SessionsHandler).Methods("GET")
+	router.HandleFunc("/admin/tools/{name}/{action}", handler.ToolStateHandler).Methods("POST")
+	router.HandleFunc("/admin/refresh", handler.RefreshHandler).Methods("POST")
+
+	return router
+}
+
+// newListener creates a TCP listener that accepts at most maxConnections concurrent
+// connections; further connections wait until one closes (0 = unlimited)
+func newListener(addr string, maxConnections int) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	if maxConnections > 0 {
+		listener = netutil.LimitListener(listener, maxConnections)
+	}
+
+	return listener, nil
+}
+
+// gracefulShutdown handles graceful shutdown of the HTTP servers
+func gracefulShutdown(logger *zap.Logger, servers ...*http.Server) {
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -86,9 +156,11 @@ func gracefulShutdown(server *http.Server, logger *zap.Logger) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Shutdown the server
-	if err := server.Shutdown(ctx); err != nil {
-		logger.Error("Server forced to shutdown", zap.Error(err))
+	// Shutdown the servers
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error("Server forced to shutdown", zap.String("addr", server.Addr), zap.Error(err))
+		}
 	}
 
 	logger.Info("Server exited")
@@ -114,20 +186,10 @@ func RegisterAndServeMCP(ctx context.Context, config *Config) {
 		zap.String("log_level", config.LogLevel),
 		zap.Bool("development", config.Development))
 
-	// Create service discoverer with FileDescriptorSet support
-	descriptorConfig := appconfig.DescriptorSetConfig{
-		Enabled:              config.DescriptorPath != "",
-		Path:                 config.DescriptorPath,
-		PreferOverReflection: false, // Use reflection as primary, descriptor as enhancement
-		IncludeSourceInfo:    true,
-	}
+	settings := config.settings()
 
-	serviceDiscoverer, err := grpc.NewServiceDiscoverer(
-		config.GRPCHost,
-		config.GRPCPort,
-		logger,
-		descriptorConfig,
-	)
+	// Create service discoverer with FileDescriptorSet support
+	serviceDiscoverer, err := grpc.NewServiceDiscovererWithConfig(logger, grpcSettings(config, settings))
 	if err != nil {
 		logger.Fatal("Failed to create service discoverer", zap.Error(err))
 	}
@@ -157,7 +219,7 @@ func RegisterAndServeMCP(ctx context.Context, config *Config) {
 		zap.Int("methodCount", serviceDiscoverer.GetMethodCount()))
 
 	// Create session manager
-	sessionManager := session.NewManager(logger)
+	sessionManager := session.NewManagerWithConfig(logger, settings.Session)
 	defer func() {
 		if err := sessionManager.Close(); err != nil {
 			logger.Warn("Failed to close session manager", zap.Error(err))
@@ -165,11 +227,21 @@ func RegisterAndServeMCP(ctx context.Context, config *Config) {
 	}()
 
 	// Create tool builder
-	toolBuilder := tools.NewMCPToolBuilder(logger)
+	toolBuilder := tools.NewMCPToolBuilderWithConfig(logger, settings.Tools)
+	toolBuilder.SetServerStreaming(settings.GRPC.ServerStreaming.Enabled)
+	if path := settings.Tools.InputSchemaOverridesPath; path != "" {
+		overrides, err := tools.LoadInputSchemaOverrides(path)
+		if err != nil {
+			logger.Fatal("Failed to load input schema overrides", zap.Error(err))
+		}
+		toolBuilder.SetInputSchemaOverrides(overrides)
+		logger.Info("Loaded input schema overrides",
+			zap.String("path", path),
+			zap.Int("toolCount", len(overrides)))
+	}
 
-	// Create HTTP handler with default header forwarding config
-	defaultConfig := appconfig.Default()
-	handler := server.NewHandler(logger, serviceDiscoverer, sessionManager, toolBuilder, defaultConfig.GRPC.HeaderForwarding)
+	// Create HTTP handler
+	handler := server.NewHandlerWithConfig(logger, serviceDiscoverer, sessionManager, toolBuilder, settings)
 
 	// Setup router
 	router := setupRouter(handler)
@@ -187,14 +259,47 @@ func RegisterAndServeMCP(ctx context.Context, config *Config) {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	listener, err := newListener(httpServer.Addr, settings.Server.MaxConnections)
+	if err != nil {
+		logger.Fatal("Failed to create HTTP listener", zap.Error(err))
+	}
+
 	// Start server in a goroutine
 	go func() {
-		logger.Info("Starting HTTP server", zap.Int("port", config.HTTPPort))
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("Starting HTTP server",
+			zap.Int("port", config.HTTPPort),
+			zap.Int("maxConnections", settings.Server.MaxConnections))
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start HTTP server", zap.Error(err))
 		}
 	}()
 
+	servers := []*http.Server{httpServer}
+
+	// Serve admin endpoints on their own listener, behind the admin token
+	if adminPort := settings.Server.Admin.Port; adminPort > 0 {
+		adminServer := &http.Server{
+			Addr:         fmt.Sprintf(":%d", adminPort),
+			Handler:      server.ChainMiddleware(server.AdminMiddleware(logger, settings.Server.Admin.Token)...)(setupAdminRouter(handler)),
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		}
+
+		adminListener, err := newListener(adminServer.Addr, 0)
+		if err != nil {
+			logger.Fatal("Failed to create admin listener", zap.Error(err))
+		}
+
+		go func() {
+			logger.Info("Starting admin server", zap.Int("port", adminPort))
+			if err := adminServer.Serve(adminListener); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Failed to start admin server", zap.Error(err))
+			}
+		}()
+		servers = append(servers, adminServer)
+	}
+
 	// Wait for shutdown signal
-	gracefulShutdown(httpServer, logger)
+	gracefulShutdown(logger, servers...)
 }
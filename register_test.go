@@ -0,0 +1,137 @@
+package ggRMCP
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appconfig "github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/grpc"
+	"github.com/lysfighting/ggRMCP/server"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewListener_LimitsConcurrentConnections(t *testing.T) {
+	listener, err := newListener("127.0.0.1:0", 1)
+	require.NoError(t, err)
+
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	httpServer := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			entered <- struct{}{}
+			<-release
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+	go func() {
+		_ = httpServer.Serve(listener)
+	}()
+	t.Cleanup(func() { _ = httpServer.Close() })
+
+	// Close connections after each response so the slot is freed for the queued client
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	url := "http://" + listener.Addr().String()
+
+	get := func(done chan<- error) {
+		resp, err := client.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		done <- err
+	}
+
+	firstDone := make(chan error, 1)
+	go get(firstDone)
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("first request was not served")
+	}
+
+	// The second connection waits while the first holds the only slot
+	secondDone := make(chan error, 1)
+	go get(secondDone)
+	select {
+	case <-entered:
+		t.Fatal("second request was served beyond the connection limit")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Releasing the first connection lets the queued one through
+	close(release)
+	assert.NoError(t, <-firstDone)
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued request was not served after the first connection closed")
+	}
+	assert.NoError(t, <-secondDone)
+}
+
+func TestNewListener_Unlimited(t *testing.T) {
+	listener, err := newListener("127.0.0.1:0", 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	// Without a limit the plain TCP listener is returned
+	assert.IsType(t, &net.TCPListener{}, listener)
+}
+
+func TestSetupAdminRouter_ToolState(t *testing.T) {
+	logger := zap.NewNop()
+	settings := appconfig.Default()
+	settings.Tools.ServiceCatalogTool = true
+
+	serviceDiscoverer, err := grpc.NewServiceDiscovererWithConfig(logger, settings.GRPC)
+	require.NoError(t, err)
+	sessionManager := session.NewManagerWithConfig(logger, settings.Session)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	handler := server.NewHandlerWithConfig(logger, serviceDiscoverer, sessionManager, tools.NewMCPToolBuilder(logger), settings)
+	admin := server.ChainMiddleware(server.AdminMiddleware(logger, "secret")...)(setupAdminRouter(handler))
+
+	post := func(path string) int {
+		// Admin actions carry no body and no Content-Type
+		req := httptest.NewRequest("POST", path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		admin.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, post("/admin/tools/"+tools.ServiceCatalogToolName+"/disable"))
+	assert.Equal(t, http.StatusNotFound, post("/admin/tools/unknown_tool/disable"))
+	assert.Equal(t, http.StatusBadRequest, post("/admin/tools/"+tools.ServiceCatalogToolName+"/toggle"))
+
+	assert.False(t, handler.IsToolEnabled(tools.ServiceCatalogToolName))
+	assert.True(t, handler.IsToolEnabled("unknown_tool"))
+}
+
+func TestGRPCSettings_KeepsDescriptorSetSettings(t *testing.T) {
+	config := &Config{GRPCHost: "backend", GRPCPort: 9000, DescriptorPath: "/tmp/services.binpb"}
+
+	// Defaults normalize paths
+	grpcConfig := grpcSettings(config, config.settings())
+	assert.Equal(t, "backend", grpcConfig.Host)
+	assert.Equal(t, 9000, grpcConfig.Port)
+	assert.True(t, grpcConfig.DescriptorSet.Enabled)
+	assert.Equal(t, "/tmp/services.binpb", grpcConfig.DescriptorSet.Path)
+	assert.True(t, grpcConfig.DescriptorSet.NormalizePaths)
+
+	// Explicit settings are carried through
+	config.Settings = appconfig.Default()
+	config.Settings.GRPC.DescriptorSet.NormalizePaths = false
+	config.Settings.GRPC.DescriptorSet.Retry.MaxAttempts = 5
+
+	grpcConfig = grpcSettings(config, config.settings())
+	assert.False(t, grpcConfig.DescriptorSet.NormalizePaths)
+	assert.Equal(t, 5, grpcConfig.DescriptorSet.Retry.MaxAttempts)
+	assert.True(t, grpcConfig.DescriptorSet.IncludeSourceInfo)
+}
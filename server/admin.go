@@ -0,0 +1,184 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/lysfighting/ggRMCP/mcp"
+	"github.com/lysfighting/ggRMCP/tools"
+	"go.uber.org/zap"
+)
+
+// SessionsHandler lists active sessions with their usage and rate limiting counters;
+// session IDs are replaced by a hash so they cannot be replayed
+func (h *Handler) SessionsHandler(w http.ResponseWriter, r *http.Request) {
+	sessions := h.sessionManager.GetActiveSessions()
+	if sessions == nil {
+		sessions = []map[string]interface{}{}
+	}
+	for _, info := range sessions {
+		if id, ok := info["id"].(string); ok {
+			info["id"] = hashSessionID(id)
+		}
+	}
+
+	response := map[string]interface{}{
+		"sessions": sessions,
+		"stats":    h.sessionManager.GetSessionStats(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode sessions", zap.Error(err))
+	}
+}
+
+// hashSessionID returns a stable, non-reversible identifier for a session ID
+func hashSessionID(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SetToolEnabled enables or disables a tool at runtime without rediscovery
+func (h *Handler) SetToolEnabled(toolName string, enabled bool) {
+	h.toolsMu.Lock()
+	defer h.toolsMu.Unlock()
+
+	if enabled {
+		delete(h.disabledTools, toolName)
+	} else {
+		h.disabledTools[toolName] = true
+	}
+}
+
+// IsToolEnabled reports whether a tool may be listed and invoked
+func (h *Handler) IsToolEnabled(toolName string) bool {
+	h.toolsMu.RLock()
+	defer h.toolsMu.RUnlock()
+	return !h.disabledTools[toolName]
+}
+
+// withoutDisabledTools filters tools disabled at runtime out of a tool list
+func (h *Handler) withoutDisabledTools(tools []mcp.Tool) []mcp.Tool {
+	h.toolsMu.RLock()
+	defer h.toolsMu.RUnlock()
+
+	if len(h.disabledTools) == 0 {
+		return tools
+	}
+
+	enabled := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if !h.disabledTools[tool.Name] {
+			enabled = append(enabled, tool)
+		}
+	}
+	return enabled
+}
+
+// disabledToolNames returns the names of tools disabled at runtime, sorted
+func (h *Handler) disabledToolNames() []string {
+	h.toolsMu.RLock()
+	defer h.toolsMu.RUnlock()
+
+	names := make([]string, 0, len(h.disabledTools))
+	for name := range h.disabledTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isKnownTool reports whether a tool name is in the current tool list, ignoring runtime state
+func (h *Handler) isKnownTool(toolName string) bool {
+	if h.serviceCatalogTool && toolName == tools.ServiceCatalogToolName {
+		return true
+	}
+
+	for _, method := range h.serviceDiscoverer.GetMethods() {
+		if h.toolBuilder.IsExposed(method) && method.GenerateToolName() == toolName {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolStateHandler enables or disables the tool named in the path
+// (POST /admin/tools/{name}/enable or /admin/tools/{name}/disable)
+func (h *Handler) ToolStateHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	toolName := vars["name"]
+
+	var enabled bool
+	switch vars["action"] {
+	case "enable":
+		enabled = true
+	case "disable":
+		enabled = false
+	default:
+		http.Error(w, "action must be enable or disable", http.StatusBadRequest)
+		return
+	}
+
+	if toolName == "" {
+		http.Error(w, "tool name is required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.isKnownTool(toolName) {
+		http.Error(w, "unknown tool: "+toolName, http.StatusNotFound)
+		return
+	}
+
+	h.SetToolEnabled(toolName, enabled)
+	h.logger.Info("Tool state changed",
+		zap.String("toolName", toolName),
+		zap.Bool("enabled", enabled))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := map[string]interface{}{
+		"tool":          toolName,
+		"enabled":       enabled,
+		"disabledTools": h.disabledToolNames(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode tool state", zap.Error(err))
+	}
+}
+
+// RefreshHandler reconnects to the gRPC server and rediscovers services,
+// recovering from a permanent connection failure
+func (h *Handler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := h.serviceDiscoverer.Refresh(r.Context()); err != nil {
+		h.logger.Error("Admin refresh failed", zap.Error(err))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if encodeErr := json.NewEncoder(w).Encode(map[string]interface{}{
+			"refreshed": false,
+			"error":     err.Error(),
+		}); encodeErr != nil {
+			h.logger.Error("Failed to encode refresh response", zap.Error(encodeErr))
+		}
+		return
+	}
+
+	h.logger.Info("Admin refresh completed")
+	w.WriteHeader(http.StatusOK)
+
+	response := map[string]interface{}{
+		"refreshed": true,
+		"stats":     h.serviceDiscoverer.GetServiceStats(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode refresh response", zap.Error(err))
+	}
+}
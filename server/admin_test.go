@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/mcp"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// setToolState calls the admin tool state endpoint for the given tool and action
+func setToolState(t *testing.T, handler *Handler, toolName, action string) map[string]interface{} {
+	req := httptest.NewRequest("POST", "/admin/tools/"+toolName+"/"+action, nil)
+	req = mux.SetURLVars(req, map[string]string{"name": toolName, "action": action})
+
+	w := httptest.NewRecorder()
+	handler.ToolStateHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+// listedToolNames returns the names of the tools currently returned by tools/list
+func listedToolNames(t *testing.T, handler *Handler) []string {
+	list, err := handler.handleToolsList(context.Background())
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(list.Tools))
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestHandler_ToolStateDisableAndEnable(t *testing.T) {
+	const toolName = "test_catalog_userservice_getuser"
+
+	mockDiscoverer := &mockServiceDiscoverer{}
+	mockDiscoverer.On("GetMethods").Return(catalogTestMethods())
+	handler := newCatalogHandler(t, mockDiscoverer, false)
+	sessionCtx := &session.Context{ID: "tool-state-session"}
+
+	require.Contains(t, listedToolNames(t, handler), toolName)
+
+	// Disabling hides the tool and rejects invocations without reaching the backend
+	response := setToolState(t, handler, toolName, "disable")
+	assert.Equal(t, false, response["enabled"])
+	assert.Equal(t, []interface{}{toolName}, response["disabledTools"])
+
+	assert.NotContains(t, listedToolNames(t, handler), toolName)
+	assert.Contains(t, listedToolNames(t, handler), "test_catalog_adminservice_ping")
+
+	_, err := handler.handleToolsCall(context.Background(), map[string]interface{}{
+		"name":      toolName,
+		"arguments": map[string]interface{}{"value": "u-1"},
+	}, sessionCtx)
+	var rpcErr *mcp.RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, mcp.ErrorCodeInvalidParams, rpcErr.Code)
+	assert.Contains(t, rpcErr.Message, "disabled")
+	mockDiscoverer.AssertNotCalled(t, "InvokeMethodByTool")
+
+	// Re-enabling restores it
+	response = setToolState(t, handler, toolName, "enable")
+	assert.Equal(t, true, response["enabled"])
+	assert.Empty(t, response["disabledTools"])
+
+	assert.Contains(t, listedToolNames(t, handler), toolName)
+	assert.True(t, handler.IsToolEnabled(toolName))
+}
+
+func TestHandler_ToolStateRejectsUnknownAction(t *testing.T) {
+	handler := newCatalogHandler(t, &mockServiceDiscoverer{}, false)
+
+	req := httptest.NewRequest("POST", "/admin/tools/some_tool/toggle", nil)
+	req = mux.SetURLVars(req, map[string]string{"name": "some_tool", "action": "toggle"})
+
+	w := httptest.NewRecorder()
+	handler.ToolStateHandler(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_RefreshHandler(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	handler := newCatalogHandler(t, mockDiscoverer, false)
+
+	mockDiscoverer.On("Refresh", mock.Anything).Return(errors.New("connection refused")).Once()
+	w := httptest.NewRecorder()
+	handler.RefreshHandler(w, httptest.NewRequest("POST", "/admin/refresh", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "connection refused")
+
+	mockDiscoverer.On("Refresh", mock.Anything).Return(nil).Once()
+	mockDiscoverer.On("GetServiceStats").Return(map[string]interface{}{"permanentFailure": false})
+	w = httptest.NewRecorder()
+	handler.RefreshHandler(w, httptest.NewRequest("POST", "/admin/refresh", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, true, response["refreshed"])
+	assert.Equal(t, false, response["stats"].(map[string]interface{})["permanentFailure"])
+}
+
+func TestSessionsHandler_HashesSessionIDs(t *testing.T) {
+	handler := newCatalogHandler(t, &mockServiceDiscoverer{}, false)
+	sessionCtx := handler.sessionManager.CreateSession(map[string]string{})
+
+	w := httptest.NewRecorder()
+	handler.SessionsHandler(w, httptest.NewRequest("GET", "/admin/sessions", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Sessions []map[string]interface{} `json:"sessions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Sessions, 1)
+
+	// The live session ID is never returned
+	assert.Equal(t, hashSessionID(sessionCtx.ID), response.Sessions[0]["id"])
+	assert.NotContains(t, w.Body.String(), sessionCtx.ID)
+}
+
+func TestAdminMiddleware_RequiresToken(t *testing.T) {
+	handler := newCatalogHandler(t, &mockServiceDiscoverer{}, false)
+	admin := ChainMiddleware(AdminMiddleware(zap.NewNop(), "secret")...)(http.HandlerFunc(handler.SessionsHandler))
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"valid token", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/sessions", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			admin.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestHandler_ToolStateRejectsUnknownTool(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	mockDiscoverer.On("GetMethods").Return(catalogTestMethods())
+	handler := newCatalogHandler(t, mockDiscoverer, false)
+
+	req := httptest.NewRequest("POST", "/admin/tools/no_such_tool/disable", nil)
+	req = mux.SetURLVars(req, map[string]string{"name": "no_such_tool", "action": "disable"})
+
+	w := httptest.NewRecorder()
+	handler.ToolStateHandler(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, handler.disabledToolNames())
+}
+
+func TestSessionsHandler_CountsRateLimitedRequests(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.Default()
+	cfg.Session.RateLimit.RequestsPerMinute = 2
+
+	sessionManager := session.NewManagerWithConfig(logger, cfg.Session)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+	handler := NewHandlerWithConfig(logger, &mockServiceDiscoverer{}, sessionManager, tools.NewMCPToolBuilder(logger), cfg)
+
+	post := func(sessionID string) (string, *mcp.RPCError) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Error *mcp.RPCError `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Header().Get("Mcp-Session-Id"), response.Error
+	}
+
+	// The noisy session goes over its limit; each rejection is counted on it alone
+	noisy, rpcErr := post("")
+	require.Nil(t, rpcErr)
+	quiet, rpcErr := post("")
+	require.Nil(t, rpcErr)
+
+	_, rpcErr = post(noisy)
+	assert.Nil(t, rpcErr)
+	for i := 0; i < 3; i++ {
+		_, rpcErr = post(noisy)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, mcp.ErrorCodeRateLimited, rpcErr.Code)
+	}
+	_, rpcErr = post(quiet)
+	assert.Nil(t, rpcErr)
+
+	counts := make(map[string]int64)
+	for _, info := range sessionManager.GetActiveSessions() {
+		counts[info["id"].(string)] = info["rate_limited_count"].(int64)
+	}
+	assert.Equal(t, map[string]int64{noisy: 3, quiet: 0}, counts)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/lysfighting/ggRMCP/types"
+	"go.uber.org/zap"
+)
+
+// streamingMethodInfo describes a discovered streaming method for operators
+type streamingMethodInfo struct {
+	Tool      string `json:"tool"`
+	Service   string `json:"service"`
+	Method    string `json:"method"`
+	Streaming string `json:"streaming"`
+	Exposed   bool   `json:"exposed"`
+}
+
+// streamingKind names the streaming mode of a method, or returns "" for unary methods
+func streamingKind(method types.MethodInfo) string {
+	switch {
+	case method.IsClientStreaming && method.IsServerStreaming:
+		return "bidirectional"
+	case method.IsClientStreaming:
+		return "client"
+	case method.IsServerStreaming:
+		return "server"
+	default:
+		return ""
+	}
+}
+
+// StreamingMethodsHandler lists discovered streaming methods and whether each is exposed as a tool.
+// Server-streaming methods are exposed with aggregated results when enabled; client and bidirectional ones never are.
+func (h *Handler) StreamingMethodsHandler(w http.ResponseWriter, r *http.Request) {
+	methods := []streamingMethodInfo{}
+	for _, method := range h.serviceDiscoverer.GetMethods() {
+		kind := streamingKind(method)
+		if kind == "" {
+			continue
+		}
+
+		methods = append(methods, streamingMethodInfo{
+			Tool:      method.GenerateToolName(),
+			Service:   method.ServiceName,
+			Method:    method.Name,
+			Streaming: kind,
+			Exposed:   h.toolBuilder.IsExposed(method),
+		})
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Tool < methods[j].Tool
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := map[string]interface{}{
+		"methods": methods,
+		"count":   len(methods),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to encode streaming methods", zap.Error(err))
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamingMethodsHandler(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	mockDiscoverer.On("GetMethods").Return([]types.MethodInfo{
+		{Name: "GetUser", ServiceName: "test.UserService"},
+		{Name: "WatchUsers", ServiceName: "test.UserService", IsServerStreaming: true},
+		{Name: "UploadUsers", ServiceName: "test.UserService", IsClientStreaming: true},
+		{Name: "SyncUsers", ServiceName: "test.UserService", IsClientStreaming: true, IsServerStreaming: true},
+	})
+	handler := newCatalogHandler(t, mockDiscoverer, false)
+
+	type listing struct {
+		Methods []streamingMethodInfo `json:"methods"`
+		Count   int                   `json:"count"`
+	}
+	list := func() listing {
+		w := httptest.NewRecorder()
+		handler.StreamingMethodsHandler(w, httptest.NewRequest("GET", "/debug/streaming-methods", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response listing
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Only streaming methods are listed; the unary GetUser is not
+	response := list()
+	assert.Equal(t, 3, response.Count)
+	assert.Equal(t, []streamingMethodInfo{
+		{Tool: "test_userservice_syncusers", Service: "test.UserService", Method: "SyncUsers", Streaming: "bidirectional", Exposed: false},
+		{Tool: "test_userservice_uploadusers", Service: "test.UserService", Method: "UploadUsers", Streaming: "client", Exposed: false},
+		{Tool: "test_userservice_watchusers", Service: "test.UserService", Method: "WatchUsers", Streaming: "server", Exposed: false},
+	}, response.Methods)
+
+	// Server streaming is exposed once enabled
+	handler.toolBuilder.SetServerStreaming(true)
+	response = list()
+	require.Len(t, response.Methods, 3)
+	assert.True(t, response.Methods[2].Exposed)
+	assert.False(t, response.Methods[1].Exposed)
+}
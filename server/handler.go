@@ -2,19 +2,27 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lysfighting/ggRMCP/config"
 	"github.com/lysfighting/ggRMCP/grpc"
 	"github.com/lysfighting/ggRMCP/headers"
+	"github.com/lysfighting/ggRMCP/logging"
 	"github.com/lysfighting/ggRMCP/mcp"
 	"github.com/lysfighting/ggRMCP/session"
 	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/lysfighting/ggRMCP/types"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Handler handles HTTP requests for the MCP gateway
@@ -25,6 +33,21 @@ type Handler struct {
 	sessionManager    *session.Manager
 	toolBuilder       *tools.MCPToolBuilder
 	headerFilter      *headers.Filter
+	redactor          *logging.Redactor
+
+	// Expose the built-in service catalog meta-tool
+	serviceCatalogTool bool
+
+	// Report codes.Unauthenticated as a credential refresh error
+	signalUnauthenticated bool
+
+	// Optional initialize extensions taken from the MCP configuration
+	instructions string
+	experimental map[string]interface{}
+
+	// Tools disabled at runtime, keyed by tool name
+	disabledTools map[string]bool
+	toolsMu       sync.RWMutex
 }
 
 // NewHandler creates a new HTTP handler
@@ -34,14 +57,34 @@ func NewHandler(
 	sessionManager *session.Manager,
 	toolBuilder *tools.MCPToolBuilder,
 	headerConfig config.HeaderForwardingConfig,
+) *Handler {
+	cfg := config.Default()
+	cfg.GRPC.HeaderForwarding = headerConfig
+	return NewHandlerWithConfig(logger, serviceDiscoverer, sessionManager, toolBuilder, cfg)
+}
+
+// NewHandlerWithConfig creates a new HTTP handler from application configuration
+func NewHandlerWithConfig(
+	logger *zap.Logger,
+	serviceDiscoverer grpc.ServiceDiscoverer,
+	sessionManager *session.Manager,
+	toolBuilder *tools.MCPToolBuilder,
+	cfg *config.Config,
 ) *Handler {
 	return &Handler{
 		logger:            logger,
-		validator:         mcp.NewValidator(),
+		validator:         mcp.NewValidatorWithConfig(cfg.MCP.Validation),
 		serviceDiscoverer: serviceDiscoverer,
 		sessionManager:    sessionManager,
 		toolBuilder:       toolBuilder,
-		headerFilter:      headers.NewFilter(headerConfig),
+		headerFilter:      headers.NewFilter(cfg.GRPC.HeaderForwarding),
+		redactor:          logging.NewRedactor(cfg.Logging.Redaction),
+
+		serviceCatalogTool:    cfg.Tools.ServiceCatalogTool,
+		signalUnauthenticated: cfg.GRPC.SignalUnauthenticated,
+		instructions:          cfg.MCP.Instructions,
+		experimental:          cfg.MCP.Experimental,
+		disabledTools:         make(map[string]bool),
 	}
 }
 
@@ -94,6 +137,9 @@ func (h *Handler) handlePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Normalize tolerated version variants now that the request is known to be valid
+	req.JSONRPC = "2.0"
+
 	// Extract session information
 	sessionID := r.Header.Get("Mcp-Session-Id")
 	sessionCtx := h.sessionManager.GetOrCreateSession(sessionID, extractHeaders(r))
@@ -101,11 +147,30 @@ func (h *Handler) handlePost(w http.ResponseWriter, r *http.Request) {
 	// Set session header in response
 	w.Header().Set("Mcp-Session-Id", sessionCtx.ID)
 
+	// Enforce the per-session rate limit; rejections are counted on the session
+	if !h.sessionManager.CheckRateLimit(sessionCtx.ID) {
+		h.writeErrorResponse(w, req.ID, mcp.ErrorCodeRateLimited, "Rate limit exceeded for session")
+		return
+	}
+
+	// Replay the original response if this tool call was already handled
+	dedupKey, dedup := h.dedupKey(&req)
+	if dedup {
+		if cached, ok := h.sessionManager.GetDuplicateResponse(sessionCtx, dedupKey); ok {
+			h.logger.Info("Replaying response for duplicate request",
+				zap.String("method", req.Method),
+				zap.String("requestId", req.ID.String()),
+				zap.String("sessionId", sessionCtx.ID))
+			h.writeJSONResponse(w, cached)
+			return
+		}
+	}
+
 	// Log the request
 	h.logger.Info("Processing MCP request",
 		zap.String("method", req.Method),
 		zap.String("sessionId", sessionCtx.ID),
-		zap.Any("params", req.Params))
+		zap.Any("params", h.loggableParams(&req)))
 
 	// Handle the request
 	result, err := h.handleRequest(r.Context(), &req, sessionCtx)
@@ -114,6 +179,13 @@ func (h *Handler) handlePost(w http.ResponseWriter, r *http.Request) {
 			zap.String("method", req.Method),
 			zap.Error(err))
 
+		// Errors that already carry a JSON-RPC code are returned as-is
+		var rpcErr *mcp.RPCError
+		if errors.As(err, &rpcErr) {
+			h.writeRPCError(w, req.ID, rpcErr)
+			return
+		}
+
 		// Determine error code
 		var errorCode int
 		if strings.Contains(err.Error(), "not found") {
@@ -135,6 +207,11 @@ func (h *Handler) handlePost(w http.ResponseWriter, r *http.Request) {
 		Result:  result,
 	}
 
+	// Remember the response so retries of the same call are not invoked twice; failed calls stay retryable
+	if dedup && !isFailedToolCall(result) {
+		h.sessionManager.RecordResponse(sessionCtx, dedupKey, response)
+	}
+
 	h.writeJSONResponse(w, response)
 }
 
@@ -151,11 +228,58 @@ func (h *Handler) handleRequest(ctx context.Context, req *mcp.JSONRPCRequest, se
 		return h.handlePromptsList(ctx)
 	case "resources/list":
 		return h.handleResourcesList(ctx)
+	case "resources/read":
+		return h.handleResourcesRead(req.Params)
 	default:
 		return nil, fmt.Errorf("method not found: %s", req.Method)
 	}
 }
 
+// isFailedToolCall reports whether a result is a tool call that the backend failed
+func isFailedToolCall(result interface{}) bool {
+	toolResult, ok := result.(*mcp.ToolCallResult)
+	return ok && toolResult.IsError
+}
+
+// dedupKey returns the key under which a tools/call response is cached for replay.
+// Other methods and disabled tools are never replayed.
+func (h *Handler) dedupKey(req *mcp.JSONRPCRequest) (string, bool) {
+	if req.Method != "tools/call" {
+		return "", false
+	}
+
+	toolName, _ := req.Params["name"].(string)
+	if !h.IsToolEnabled(toolName) {
+		return "", false
+	}
+
+	// Include the params so a reused ID with different arguments is invoked fresh
+	paramsJSON, err := json.Marshal(req.Params)
+	if err != nil {
+		return "", false
+	}
+	paramsHash := sha256.Sum256(paramsJSON)
+
+	return req.Method + "|" + req.ID.Key() + "|" + hex.EncodeToString(paramsHash[:]), true
+}
+
+// loggableParams returns request params with tool call arguments redacted for logging
+func (h *Handler) loggableParams(req *mcp.JSONRPCRequest) map[string]interface{} {
+	if req.Method != "tools/call" || req.Params == nil {
+		return req.Params
+	}
+
+	toolName, _ := req.Params["name"].(string)
+	params := make(map[string]interface{}, len(req.Params))
+	for key, value := range req.Params {
+		params[key] = value
+	}
+	if args, exists := params["arguments"]; exists {
+		params["arguments"] = h.redactor.Redact(toolName, args)
+	}
+	return params
+}
+
 // handleInitialize handles the initialize method
 func (h *Handler) handleInitialize() *mcp.InitializationResult {
 	return &mcp.InitializationResult{
@@ -170,11 +294,13 @@ func (h *Handler) handleInitialize() *mcp.InitializationResult {
 			Resources: &mcp.ResourcesCapability{
 				ListChanged: false,
 			},
+			Experimental: h.experimental,
 		},
 		ServerInfo: mcp.ServerInfo{
 			Name:    "ggRMCP",
 			Version: "1.0.0",
 		},
+		Instructions: h.instructions,
 	}
 }
 
@@ -204,6 +330,11 @@ func (h *Handler) handleToolsList(ctx context.Context) (*mcp.ToolsListResult, er
 		return nil, fmt.Errorf("failed to build tools: %w", err)
 	}
 
+	if h.serviceCatalogTool {
+		tools = h.withServiceCatalogTool(tools)
+	}
+	tools = h.withoutDisabledTools(tools)
+
 	h.logger.Info("Generated tools list", zap.Int("toolCount", len(tools)))
 
 	return &mcp.ToolsListResult{
@@ -221,6 +352,18 @@ func (h *Handler) handleToolsCall(ctx context.Context, params map[string]interfa
 	// Extract tool name and arguments
 	toolName := params["name"].(string)
 
+	if !h.IsToolEnabled(toolName) {
+		return nil, &mcp.RPCError{
+			Code:    mcp.ErrorCodeInvalidParams,
+			Message: fmt.Sprintf("tool %s is disabled", toolName),
+		}
+	}
+
+	// The service catalog meta-tool is answered locally without a gRPC call
+	if h.serviceCatalogTool && toolName == tools.ServiceCatalogToolName {
+		return h.handleServiceCatalogCall(sessionCtx)
+	}
+
 	var argumentsJSON string
 	if args, exists := params["arguments"]; exists && args != nil {
 		argBytes, err := json.Marshal(args)
@@ -232,13 +375,23 @@ func (h *Handler) handleToolsCall(ctx context.Context, params map[string]interfa
 
 	h.logger.Debug("Invoking tool",
 		zap.String("toolName", toolName),
-		zap.String("arguments", argumentsJSON),
+		zap.Any("arguments", h.redactor.Redact(toolName, params["arguments"])),
 		zap.String("sessionId", sessionCtx.ID))
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// Attach per-call invocation options
+	var invokeOpts types.InvokeOptions
+	if format, ok := params["responseFormat"].(string); ok {
+		invokeOpts.ResponseFormat = types.ResponseFormat(format)
+	}
+	if compression, ok := params["compression"].(string); ok {
+		invokeOpts.Compression = compression
+	}
+	ctx = grpc.WithInvokeOptions(ctx, invokeOpts)
+
 	// Filter headers for forwarding
 	filteredHeaders := h.headerFilter.FilterHeaders(sessionCtx.Headers)
 
@@ -250,6 +403,12 @@ func (h *Handler) handleToolsCall(ctx context.Context, params map[string]interfa
 	// Invoke the gRPC method by tool name with filtered headers
 	result, err := h.serviceDiscoverer.InvokeMethodByTool(ctx, filteredHeaders, toolName, argumentsJSON)
 	if err != nil {
+		if h.signalUnauthenticated && status.Code(err) == codes.Unauthenticated {
+			h.logger.Warn("Backend rejected credentials, asking client to refresh",
+				zap.String("toolName", toolName),
+				zap.String("sessionId", sessionCtx.ID))
+			return nil, unauthenticatedError(toolName)
+		}
 		return &mcp.ToolCallResult{
 			Content: []mcp.ContentBlock{
 				mcp.TextContent(fmt.Sprintf("Error invoking method: %s", mcp.SanitizeError(err))),
@@ -270,6 +429,40 @@ func (h *Handler) handleToolsCall(ctx context.Context, params map[string]interfa
 	}, nil
 }
 
+// withServiceCatalogTool appends the service catalog meta-tool, dropping any discovered tool with the same name
+func (h *Handler) withServiceCatalogTool(discovered []mcp.Tool) []mcp.Tool {
+	result := make([]mcp.Tool, 0, len(discovered)+1)
+	for _, tool := range discovered {
+		if tool.Name == tools.ServiceCatalogToolName {
+			h.logger.Warn("Discovered tool collides with the service catalog tool, skipping",
+				zap.String("toolName", tool.Name))
+			continue
+		}
+		result = append(result, tool)
+	}
+	return append(result, tools.ServiceCatalogTool())
+}
+
+// handleServiceCatalogCall returns the catalog of discovered services as a tool result
+func (h *Handler) handleServiceCatalogCall(sessionCtx *session.Context) (*mcp.ToolCallResult, error) {
+	catalog := h.toolBuilder.BuildServiceCatalog(h.serviceDiscoverer.GetMethods(), h.IsToolEnabled)
+
+	catalogJSON, err := json.Marshal(catalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal service catalog: %w", err)
+	}
+
+	sessionCtx.IncrementCallCount()
+	sessionCtx.UpdateLastAccessed()
+
+	return &mcp.ToolCallResult{
+		Content: []mcp.ContentBlock{
+			mcp.TextContent(string(catalogJSON)),
+		},
+		IsError: false,
+	}, nil
+}
+
 // handlePromptsList handles the prompts/list method
 func (h *Handler) handlePromptsList(ctx context.Context) (interface{}, error) {
 	// Return empty prompts list since this implementation focuses on tools
@@ -286,6 +479,43 @@ func (h *Handler) handleResourcesList(ctx context.Context) (interface{}, error)
 	}, nil
 }
 
+// handleResourcesRead handles the resources/read method, resolving lazy "schema://" references
+func (h *Handler) handleResourcesRead(params map[string]interface{}) (*mcp.ResourceReadResult, error) {
+	uri, _ := params["uri"].(string)
+	if uri == "" {
+		return nil, &mcp.RPCError{
+			Code:    mcp.ErrorCodeInvalidParams,
+			Message: "resource uri is required",
+		}
+	}
+
+	schema, err := h.toolBuilder.ResolveSchemaURI(uri, h.serviceDiscoverer.GetMethods())
+	if err != nil {
+		h.logger.Warn("Failed to resolve schema resource",
+			zap.String("uri", uri),
+			zap.Error(err))
+		return nil, &mcp.RPCError{
+			Code:    mcp.ErrorCodeInvalidParams,
+			Message: fmt.Sprintf("resource %s could not be resolved", uri),
+		}
+	}
+
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema for %s: %w", uri, err)
+	}
+
+	return &mcp.ResourceReadResult{
+		Contents: []mcp.ResourceContents{
+			{
+				URI:      uri,
+				MimeType: "application/schema+json",
+				Text:     string(schemaJSON),
+			},
+		},
+	}, nil
+}
+
 // writeJSONResponse writes a JSON response
 func (h *Handler) writeJSONResponse(w http.ResponseWriter, response interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -296,15 +526,33 @@ func (h *Handler) writeJSONResponse(w http.ResponseWriter, response interface{})
 	}
 }
 
+// unauthenticatedError builds the error telling clients to refresh credentials for a tool
+func unauthenticatedError(toolName string) *mcp.RPCError {
+	return &mcp.RPCError{
+		Code:    mcp.ErrorCodeUnauthenticated,
+		Message: "Authentication required",
+		Data: map[string]interface{}{
+			"reason": "unauthenticated",
+			"action": "refresh_credentials",
+			"tool":   toolName,
+		},
+	}
+}
+
 // writeErrorResponse writes an error response
 func (h *Handler) writeErrorResponse(w http.ResponseWriter, id mcp.RequestID, code int, message string) {
+	h.writeRPCError(w, id, &mcp.RPCError{
+		Code:    code,
+		Message: message,
+	})
+}
+
+// writeRPCError writes a JSON-RPC error response
+func (h *Handler) writeRPCError(w http.ResponseWriter, id mcp.RequestID, rpcErr *mcp.RPCError) {
 	response := &mcp.JSONRPCResponse{
 		JSONRPC: "2.0",
 		ID:      id,
-		Error: &mcp.RPCError{
-			Code:    code,
-			Message: message,
-		},
+		Error:   rpcErr,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/mcp"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newUnauthenticatedHandler(t *testing.T, signal bool) *Handler {
+	logger := zap.NewNop()
+
+	sessionManager := session.NewManager(logger)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	mockDiscoverer := &mockServiceDiscoverer{}
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_testmethod",
+		`{"input":"test"}`,
+	).Return("", fmt.Errorf("failed to invoke method: %w", status.Error(codes.Unauthenticated, "token expired")))
+
+	cfg := config.Default()
+	cfg.GRPC.SignalUnauthenticated = signal
+
+	return NewHandlerWithConfig(logger, mockDiscoverer, sessionManager, tools.NewMCPToolBuilder(logger), cfg)
+}
+
+func TestHandler_UnauthenticatedSignalsCredentialRefresh(t *testing.T) {
+	handler := newUnauthenticatedHandler(t, true)
+
+	w := postToolsCall(t, handler, 1, "")
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *mcp.RPCError   `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Empty(t, response.Result)
+	assert.Equal(t, mcp.ErrorCodeUnauthenticated, response.Error.Code)
+
+	data, ok := response.Error.Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "refresh_credentials", data["action"])
+	assert.Equal(t, "test_service_testmethod", data["tool"])
+}
+
+func TestHandler_UnauthenticatedDefaultsToToolError(t *testing.T) {
+	handler := newUnauthenticatedHandler(t, false)
+
+	w := postToolsCall(t, handler, 1, "")
+
+	var response struct {
+		Result *mcp.ToolCallResult `json:"result"`
+		Error  *mcp.RPCError       `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Error)
+	require.NotNil(t, response.Result)
+	assert.True(t, response.Result.IsError)
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// catalogTestMethods returns methods from two services for catalog tests
+func catalogTestMethods() []types.MethodInfo {
+	emptyDesc := (&emptypb.Empty{}).ProtoReflect().Descriptor()
+	stringDesc := (&wrapperspb.StringValue{}).ProtoReflect().Descriptor()
+
+	return []types.MethodInfo{
+		{
+			Name:             "GetUser",
+			FullName:         "test.catalog.UserService.GetUser",
+			ServiceName:      "test.catalog.UserService",
+			PackageName:      "test.catalog",
+			FileName:         "test/catalog/user.proto",
+			Description:      "Fetches a user",
+			InputDescriptor:  stringDesc,
+			OutputDescriptor: stringDesc,
+		},
+		{
+			Name:              "WatchUsers",
+			FullName:          "test.catalog.UserService.WatchUsers",
+			ServiceName:       "test.catalog.UserService",
+			PackageName:       "test.catalog",
+			FileName:          "test/catalog/user.proto",
+			InputDescriptor:   emptyDesc,
+			OutputDescriptor:  stringDesc,
+			IsServerStreaming: true,
+		},
+		{
+			Name:             "Ping",
+			FullName:         "test.catalog.AdminService.Ping",
+			ServiceName:      "test.catalog.AdminService",
+			PackageName:      "test.catalog",
+			FileName:         "test/catalog/admin.proto",
+			InputDescriptor:  emptyDesc,
+			OutputDescriptor: emptyDesc,
+		},
+	}
+}
+
+func newCatalogHandler(t *testing.T, mockDiscoverer *mockServiceDiscoverer, enabled bool) *Handler {
+	logger := zap.NewNop()
+
+	sessionManager := session.NewManager(logger)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	cfg := config.Default()
+	cfg.Tools.ServiceCatalogTool = enabled
+
+	return NewHandlerWithConfig(logger, mockDiscoverer, sessionManager, tools.NewMCPToolBuilder(logger), cfg)
+}
+
+func TestHandler_ServiceCatalogTool(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	mockDiscoverer.On("GetMethods").Return(catalogTestMethods())
+	handler := newCatalogHandler(t, mockDiscoverer, true)
+
+	// The meta-tool is listed alongside the discovered tools
+	list, err := handler.handleToolsList(context.Background())
+	require.NoError(t, err)
+
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.Contains(t, names, tools.ServiceCatalogToolName)
+	assert.Contains(t, names, "test_catalog_userservice_getuser")
+
+	// Invoking it returns the catalog without calling the backend
+	handler.SetToolEnabled("test_catalog_adminservice_ping", false)
+	sessionCtx := &session.Context{ID: "catalog-session"}
+	result, err := handler.HandleToolsCall(context.Background(), map[string]interface{}{
+		"name": tools.ServiceCatalogToolName,
+	}, sessionCtx)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	mockDiscoverer.AssertNotCalled(t, "InvokeMethodByTool")
+
+	var catalog tools.ServiceCatalog
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &catalog))
+	require.Len(t, catalog.Services, 2)
+
+	admin := catalog.Services[0]
+	assert.Equal(t, "test.catalog.AdminService", admin.Name)
+	assert.Equal(t, "test/catalog/admin.proto", admin.File)
+	require.Len(t, admin.Methods, 1)
+	assert.Equal(t, "google.protobuf.Empty", admin.Methods[0].InputType)
+	assert.Equal(t, "test_catalog_adminservice_ping", admin.Methods[0].Tool)
+	assert.False(t, admin.Methods[0].Enabled)
+
+	users := catalog.Services[1]
+	assert.Equal(t, "test.catalog.UserService", users.Name)
+	assert.Equal(t, "test.catalog", users.Package)
+	require.Len(t, users.Methods, 2)
+	assert.Equal(t, "GetUser", users.Methods[0].Name)
+	assert.Equal(t, "test_catalog_userservice_getuser", users.Methods[0].Tool)
+	assert.True(t, users.Methods[0].Enabled)
+	assert.Equal(t, "Fetches a user", users.Methods[0].Description)
+	assert.Equal(t, "google.protobuf.StringValue", users.Methods[0].OutputType)
+	assert.Equal(t, "WatchUsers", users.Methods[1].Name)
+	assert.True(t, users.Methods[1].ServerStreaming)
+
+	// Server streaming is not exposed by default, so the method has no tool
+	assert.Empty(t, users.Methods[1].Tool)
+	assert.False(t, users.Methods[1].Enabled)
+}
+
+func TestHandler_ServiceCatalogToolDisabled(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	mockDiscoverer.On("GetMethods").Return(catalogTestMethods())
+	handler := newCatalogHandler(t, mockDiscoverer, false)
+
+	list, err := handler.handleToolsList(context.Background())
+	require.NoError(t, err)
+	for _, tool := range list.Tools {
+		assert.NotEqual(t, tools.ServiceCatalogToolName, tool.Name)
+	}
+}
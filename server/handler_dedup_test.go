@@ -0,0 +1,182 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/mcp"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// postToolsCall sends a tools/call request with the given ID and session and returns the recorder
+func postToolsCall(t *testing.T, handler *Handler, id interface{}, sessionID string) *httptest.ResponseRecorder {
+	requestBody := mcp.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.RequestID{Value: id},
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name": "test_service_testmethod",
+			"arguments": map[string]interface{}{
+				"input": "test",
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	return w
+}
+
+func newDedupHandler(t *testing.T, mockDiscoverer *mockServiceDiscoverer) *Handler {
+	logger := zap.NewNop()
+
+	sessionConfig := config.Default().Session
+	sessionConfig.DedupWindow = time.Minute
+	sessionManager := session.NewManagerWithConfig(logger, sessionConfig)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	toolBuilder := tools.NewMCPToolBuilder(logger)
+
+	return NewHandler(logger, mockDiscoverer, sessionManager, toolBuilder, config.HeaderForwardingConfig{})
+}
+
+func TestHandler_DuplicateRequestIDReturnsCachedResponse(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	handler := newDedupHandler(t, mockDiscoverer)
+
+	// The backend must only be invoked once for the retried request
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_testmethod",
+		`{"input":"test"}`,
+	).Return(`{"output":"first"}`, nil).Once()
+
+	first := postToolsCall(t, handler, 7, "")
+	sessionID := first.Header().Get("Mcp-Session-Id")
+	require.NotEmpty(t, sessionID)
+
+	second := postToolsCall(t, handler, 7, sessionID)
+
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+	assert.Contains(t, second.Body.String(), "first")
+	mockDiscoverer.AssertNumberOfCalls(t, "InvokeMethodByTool", 1)
+}
+
+func TestHandler_FailedToolCallIsNotReplayed(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	handler := newDedupHandler(t, mockDiscoverer)
+
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_testmethod",
+		`{"input":"test"}`,
+	).Return("", errors.New("backend unavailable")).Once()
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_testmethod",
+		`{"input":"test"}`,
+	).Return(`{"output":"recovered"}`, nil).Once()
+
+	first := postToolsCall(t, handler, 9, "")
+	sessionID := first.Header().Get("Mcp-Session-Id")
+	require.NotEmpty(t, sessionID)
+	assert.Contains(t, first.Body.String(), "backend unavailable")
+
+	// The retry of a failed call must reach the backend again
+	second := postToolsCall(t, handler, 9, sessionID)
+
+	assert.Contains(t, second.Body.String(), "recovered")
+	mockDiscoverer.AssertNumberOfCalls(t, "InvokeMethodByTool", 2)
+}
+
+func TestHandler_DistinctRequestIDInvokesFresh(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	handler := newDedupHandler(t, mockDiscoverer)
+
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_testmethod",
+		`{"input":"test"}`,
+	).Return(`{"output":"first"}`, nil).Once()
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_testmethod",
+		`{"input":"test"}`,
+	).Return(`{"output":"second"}`, nil).Once()
+
+	first := postToolsCall(t, handler, 1, "")
+	sessionID := first.Header().Get("Mcp-Session-Id")
+	require.NotEmpty(t, sessionID)
+
+	// A numeric ID and a string ID with the same text are distinct requests
+	second := postToolsCall(t, handler, "1", sessionID)
+
+	assert.Contains(t, first.Body.String(), "first")
+	assert.Contains(t, second.Body.String(), "second")
+	mockDiscoverer.AssertNumberOfCalls(t, "InvokeMethodByTool", 2)
+}
+
+func TestHandler_DedupOnlyReplaysMatchingToolCalls(t *testing.T) {
+	mockDiscoverer := &mockServiceDiscoverer{}
+	handler := newDedupHandler(t, mockDiscoverer)
+
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_testmethod",
+		`{"input":"test"}`,
+	).Return(`{"output":"called"}`, nil).Once()
+
+	// An initialize with the same ID is not cached and must not be replayed for tools/call
+	body := []byte(`{"jsonrpc":"2.0","id":3,"method":"initialize","params":{}}`)
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	initW := httptest.NewRecorder()
+	handler.ServeHTTP(initW, req)
+	require.Equal(t, http.StatusOK, initW.Code)
+	sessionID := initW.Header().Get("Mcp-Session-Id")
+	require.NotEmpty(t, sessionID)
+
+	call := postToolsCall(t, handler, 3, sessionID)
+	assert.Contains(t, call.Body.String(), "called")
+	mockDiscoverer.AssertNumberOfCalls(t, "InvokeMethodByTool", 1)
+
+	// Once the tool is disabled, a retry is rejected instead of replayed
+	handler.SetToolEnabled("test_service_testmethod", false)
+	retry := postToolsCall(t, handler, 3, sessionID)
+
+	var response struct {
+		Error *mcp.RPCError `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(retry.Body.Bytes(), &response))
+	require.NotNil(t, response.Error)
+	assert.Contains(t, response.Error.Message, "disabled")
+	mockDiscoverer.AssertNumberOfCalls(t, "InvokeMethodByTool", 1)
+}
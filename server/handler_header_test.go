@@ -52,6 +52,11 @@ func (m *mockServiceDiscoverer) Reconnect(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *mockServiceDiscoverer) Refresh(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 func (m *mockServiceDiscoverer) IsConnected() bool {
 	args := m.Called()
 	return args.Bool(0)
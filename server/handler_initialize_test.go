@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// postInitialize sends an initialize request and returns the decoded result object
+func postInitialize(t *testing.T, cfg *config.Config) map[string]interface{} {
+	logger := zap.NewNop()
+
+	sessionManager := session.NewManager(logger)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	handler := NewHandlerWithConfig(logger, &mockServiceDiscoverer{}, sessionManager, tools.NewMCPToolBuilder(logger), cfg)
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Result map[string]interface{} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Result)
+	return response.Result
+}
+
+func TestHandler_InitializeIncludesConfiguredExtensions(t *testing.T) {
+	cfg := config.Default()
+	cfg.MCP.Instructions = "Use the staging tenant for all write operations."
+	cfg.MCP.Experimental = map[string]interface{}{
+		"deployment": map[string]interface{}{"region": "eu-west-1"},
+	}
+
+	result := postInitialize(t, cfg)
+
+	assert.Equal(t, "Use the staging tenant for all write operations.", result["instructions"])
+
+	capabilities := result["capabilities"].(map[string]interface{})
+	experimental, ok := capabilities["experimental"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"region": "eu-west-1"}, experimental["deployment"])
+	assert.Contains(t, capabilities, "tools")
+}
+
+func TestHandler_InitializeOmitsUnconfiguredExtensions(t *testing.T) {
+	result := postInitialize(t, config.Default())
+
+	assert.NotContains(t, result, "instructions")
+	assert.NotContains(t, result["capabilities"], "experimental")
+	assert.Equal(t, "ggRMCP", result["serverInfo"].(map[string]interface{})["name"])
+}
+
+func TestHandler_LenientVersionIsNormalized(t *testing.T) {
+	logger := zap.NewNop()
+
+	sessionManager := session.NewManager(logger)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	cfg := config.Default()
+	cfg.MCP.Validation.LenientJSONRPCVersion = true
+	handler := NewHandlerWithConfig(logger, &mockServiceDiscoverer{}, sessionManager, tools.NewMCPToolBuilder(logger), cfg)
+
+	body := []byte(`{"jsonrpc":"2","id":1,"method":"initialize","params":{}}`)
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "2.0", response["jsonrpc"])
+	assert.NotContains(t, response, "error")
+	assert.Contains(t, response, "result")
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/mcp"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestHandler_RedactsToolArgumentsInLogs(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	cfg := config.Default()
+	cfg.Logging.Redaction = map[string][]string{
+		"test_service_login": {"credentials.password"},
+	}
+
+	sessionManager := session.NewManager(logger)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	mockDiscoverer := &mockServiceDiscoverer{}
+	handler := NewHandlerWithConfig(logger, mockDiscoverer, sessionManager, tools.NewMCPToolBuilder(logger), cfg)
+
+	// The backend still receives the real argument value
+	mockDiscoverer.On("InvokeMethodByTool",
+		mock.Anything,
+		map[string]string{},
+		"test_service_login",
+		`{"credentials":{"password":"hunter2","username":"alice"}}`,
+	).Return(`{"ok":true}`, nil)
+
+	requestBody := mcp.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.RequestID{Value: 1},
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name": "test_service_login",
+			"arguments": map[string]interface{}{
+				"credentials": map[string]interface{}{
+					"username": "alice",
+					"password": "hunter2",
+				},
+			},
+		},
+	}
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockDiscoverer.AssertExpectations(t)
+
+	// Every logged copy of the arguments has the password masked but keeps other fields
+	argumentLogs := 0
+	for _, entry := range logs.All() {
+		fields, err := json.Marshal(entry.ContextMap())
+		require.NoError(t, err)
+		assert.NotContains(t, string(fields), "hunter2", "entry %q leaked the password", entry.Message)
+
+		if bytes.Contains(fields, []byte("credentials")) {
+			argumentLogs++
+			assert.Contains(t, string(fields), "[REDACTED]")
+			assert.Contains(t, string(fields), "alice")
+		}
+	}
+	assert.Equal(t, 2, argumentLogs)
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/mcp"
+	"github.com/lysfighting/ggRMCP/session"
+	"github.com/lysfighting/ggRMCP/tools"
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// postJSONRPC sends a JSON-RPC request and decodes the response
+func postJSONRPC(t *testing.T, handler *Handler, method string, params map[string]interface{}, result interface{}) *mcp.RPCError {
+	bodyBytes, err := json.Marshal(mcp.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.RequestID{Value: method},
+		Method:  method,
+		Params:  params,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *mcp.RPCError   `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if response.Error != nil {
+		return response.Error
+	}
+	require.NoError(t, json.Unmarshal(response.Result, result))
+	return nil
+}
+
+func TestHandler_LazySchemaResourcesRead(t *testing.T) {
+	logger := zap.NewNop()
+	fileDesc := (&descriptorpb.FileDescriptorProto{}).ProtoReflect().Descriptor()
+
+	mockDiscoverer := &mockServiceDiscoverer{}
+	mockDiscoverer.On("GetMethods").Return([]types.MethodInfo{
+		{
+			Name:             "Register",
+			FullName:         "test.lazy.RegistryService.Register",
+			ServiceName:      "test.lazy.RegistryService",
+			InputDescriptor:  fileDesc,
+			OutputDescriptor: fileDesc,
+		},
+	})
+
+	sessionManager := session.NewManager(logger)
+	t.Cleanup(func() { _ = sessionManager.Close() })
+
+	cfg := config.Default()
+	cfg.Tools.LazySchemas = true
+	handler := NewHandlerWithConfig(logger, mockDiscoverer, sessionManager,
+		tools.NewMCPToolBuilderWithConfig(logger, cfg.Tools), cfg)
+
+	// tools/list carries a placeholder for the nested message
+	var list mcp.ToolsListResult
+	require.Nil(t, postJSONRPC(t, handler, "tools/list", nil, &list))
+	require.Len(t, list.Tools, 1)
+	inputSchema := list.Tools[0].InputSchema.(map[string]interface{})
+	messageType := inputSchema["properties"].(map[string]interface{})["message_type"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "schema://google.protobuf.DescriptorProto"}, messageType["items"])
+
+	// resources/read resolves it
+	var read mcp.ResourceReadResult
+	require.Nil(t, postJSONRPC(t, handler, "resources/read", map[string]interface{}{
+		"uri": "schema://google.protobuf.DescriptorProto",
+	}, &read))
+	require.Len(t, read.Contents, 1)
+	assert.Equal(t, "application/schema+json", read.Contents[0].MimeType)
+
+	var definition map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(read.Contents[0].Text), &definition))
+	field := definition["properties"].(map[string]interface{})["field"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "schema://google.protobuf.FieldDescriptorProto"}, field["items"])
+
+	// Unknown definitions return an error
+	rpcErr := postJSONRPC(t, handler, "resources/read", map[string]interface{}{
+		"uri": "schema://test.lazy.Missing",
+	}, &read)
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, mcp.ErrorCodeInvalidParams, rpcErr.Code)
+}
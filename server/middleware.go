@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"strings"
 	"time"
@@ -160,6 +161,24 @@ func ContentTypeMiddleware(allowedTypes ...string) Middleware {
 	}
 }
 
+// AdminAuthMiddleware rejects requests that do not carry the admin bearer token
+func AdminAuthMiddleware(token string) Middleware {
+	expected := []byte("Bearer " + token)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := []byte(r.Header.Get("Authorization"))
+			if token == "" || subtle.ConstantTimeCompare(provided, expected) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // RequestSizeMiddleware limits request body size
 func RequestSizeMiddleware(maxBytes int64) Middleware {
 	return func(next http.Handler) http.Handler {
@@ -291,3 +310,13 @@ func DefaultMiddleware(logger *zap.Logger) []Middleware {
 		ValidateJSONRPC(),
 	}
 }
+
+// AdminMiddleware returns the middleware for the admin listener
+func AdminMiddleware(logger *zap.Logger, token string) []Middleware {
+	return []Middleware{
+		RecoveryMiddleware(logger),
+		LoggingMiddleware(logger),
+		AdminAuthMiddleware(token),
+		TimeoutMiddleware(30 * time.Second),
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/lysfighting/ggRMCP/config"
 	gocache "github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 )
@@ -23,16 +24,26 @@ type Context struct {
 	RemoteAddr   string            `json:"remote_addr"`
 
 	// Rate limiting
-	RequestCount int64     `json:"request_count"`
-	WindowStart  time.Time `json:"window_start"`
+	RequestCount     int64     `json:"request_count"`
+	WindowStart      time.Time `json:"window_start"`
+	RateLimitedCount int64     `json:"rate_limited_count"`
 
 	// Security
 	IsBlocked bool `json:"is_blocked"`
 
+	// Responses recorded for request de-duplication, keyed by JSON-RPC id
+	responses map[string]cachedResponse
+
 	// Synchronization
 	mu sync.RWMutex
 }
 
+// cachedResponse is a response kept for replay to retried requests
+type cachedResponse struct {
+	response  interface{}
+	expiresAt time.Time
+}
+
 // Manager manages user sessions
 type Manager struct {
 	cache  *gocache.Cache
@@ -47,6 +58,9 @@ type Manager struct {
 	// Rate limiting
 	requestsPerMinute int
 	windowSize        time.Duration
+
+	// Request de-duplication
+	dedupWindow time.Duration
 }
 
 // NewManager creates a new session manager
@@ -56,7 +70,7 @@ func NewManager(logger *zap.Logger) *Manager {
 
 	return &Manager{
 		cache:             gocache.New(defaultExpiration, cleanupInterval),
-		logger:            logger,
+		logger:            logger.Named("session"),
 		defaultExpiration: defaultExpiration,
 		cleanupInterval:   cleanupInterval,
 		maxSessions:       10000,
@@ -65,6 +79,45 @@ func NewManager(logger *zap.Logger) *Manager {
 	}
 }
 
+// NewManagerWithConfig creates a new session manager from session configuration
+func NewManagerWithConfig(logger *zap.Logger, cfg config.SessionConfig) *Manager {
+	defaultExpiration := 30 * time.Minute
+	if cfg.Expiration > 0 {
+		defaultExpiration = cfg.Expiration
+	}
+
+	cleanupInterval := 5 * time.Minute
+	if cfg.CleanupInterval > 0 {
+		cleanupInterval = cfg.CleanupInterval
+	}
+
+	maxSessions := 10000
+	if cfg.MaxSessions > 0 {
+		maxSessions = cfg.MaxSessions
+	}
+
+	requestsPerMinute := 100
+	if cfg.RateLimit.RequestsPerMinute > 0 {
+		requestsPerMinute = cfg.RateLimit.RequestsPerMinute
+	}
+
+	windowSize := time.Minute
+	if cfg.RateLimit.WindowSize > 0 {
+		windowSize = cfg.RateLimit.WindowSize
+	}
+
+	return &Manager{
+		cache:             gocache.New(defaultExpiration, cleanupInterval),
+		logger:            logger.Named("session"),
+		defaultExpiration: defaultExpiration,
+		cleanupInterval:   cleanupInterval,
+		maxSessions:       maxSessions,
+		requestsPerMinute: requestsPerMinute,
+		windowSize:        windowSize,
+		dedupWindow:       cfg.DedupWindow,
+	}
+}
+
 // GetOrCreateSession gets an existing session or creates a new one
 func (m *Manager) GetOrCreateSession(sessionID string, headers map[string]string) *Context {
 	// If no session ID provided, create a new session
@@ -194,9 +247,11 @@ func (m *Manager) CheckRateLimit(sessionID string) bool {
 
 	// Check if rate limit exceeded
 	if ctx.RequestCount >= int64(m.requestsPerMinute) {
+		ctx.RateLimitedCount++
 		m.logger.Warn("Rate limit exceeded",
 			zap.String("sessionId", sessionID),
 			zap.Int64("requestCount", ctx.RequestCount),
+			zap.Int64("rateLimitedCount", ctx.RateLimitedCount),
 			zap.Int("limit", m.requestsPerMinute))
 		return false
 	}
@@ -207,6 +262,52 @@ func (m *Manager) CheckRateLimit(sessionID string) bool {
 	return true
 }
 
+// GetDuplicateResponse returns the response previously recorded under the given
+// request key if it is still within the de-duplication window
+func (m *Manager) GetDuplicateResponse(ctx *Context, requestKey string) (interface{}, bool) {
+	if m.dedupWindow <= 0 || ctx == nil {
+		return nil, false
+	}
+
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+
+	cached, exists := ctx.responses[requestKey]
+	if !exists || time.Now().After(cached.expiresAt) {
+		return nil, false
+	}
+
+	return cached.response, true
+}
+
+// RecordResponse records a response so that retries with the same request key
+// within the de-duplication window replay it instead of invoking again
+func (m *Manager) RecordResponse(ctx *Context, requestKey string, response interface{}) {
+	if m.dedupWindow <= 0 || ctx == nil {
+		return
+	}
+
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	now := time.Now()
+	if ctx.responses == nil {
+		ctx.responses = make(map[string]cachedResponse)
+	}
+
+	// Drop expired entries so the cache stays bounded by the window
+	for id, cached := range ctx.responses {
+		if now.After(cached.expiresAt) {
+			delete(ctx.responses, id)
+		}
+	}
+
+	ctx.responses[requestKey] = cachedResponse{
+		response:  response,
+		expiresAt: now.Add(m.dedupWindow),
+	}
+}
+
 // GetSessionStats returns session statistics
 func (m *Manager) GetSessionStats() map[string]interface{} {
 	m.mu.RLock()
@@ -218,6 +319,7 @@ func (m *Manager) GetSessionStats() map[string]interface{} {
 		"default_expiration":  m.defaultExpiration.String(),
 		"cleanup_interval":    m.cleanupInterval.String(),
 		"requests_per_minute": m.requestsPerMinute,
+		"dedup_window":        m.dedupWindow.String(),
 	}
 
 	return stats
@@ -231,14 +333,15 @@ func (m *Manager) GetActiveSessions() []map[string]interface{} {
 		if ctx, ok := item.Object.(*Context); ok {
 			ctx.mu.RLock()
 			sessionInfo := map[string]interface{}{
-				"id":            sessionID,
-				"created_at":    ctx.CreatedAt,
-				"last_accessed": ctx.LastAccessed,
-				"call_count":    atomic.LoadInt64(&ctx.CallCount),
-				"user_agent":    ctx.UserAgent,
-				"remote_addr":   ctx.RemoteAddr,
-				"is_blocked":    ctx.IsBlocked,
-				"request_count": ctx.RequestCount,
+				"id":                 sessionID,
+				"created_at":         ctx.CreatedAt,
+				"last_accessed":      ctx.LastAccessed,
+				"call_count":         atomic.LoadInt64(&ctx.CallCount),
+				"user_agent":         ctx.UserAgent,
+				"remote_addr":        ctx.RemoteAddr,
+				"is_blocked":         ctx.IsBlocked,
+				"request_count":      ctx.RequestCount,
+				"rate_limited_count": ctx.RateLimitedCount,
 			}
 			ctx.mu.RUnlock()
 			sessions = append(sessions, sessionInfo)
@@ -334,14 +437,15 @@ func (ctx *Context) GetInfo() map[string]interface{} {
 	defer ctx.mu.RUnlock()
 
 	return map[string]interface{}{
-		"id":            ctx.ID,
-		"created_at":    ctx.CreatedAt,
-		"last_accessed": ctx.LastAccessed,
-		"call_count":    atomic.LoadInt64(&ctx.CallCount),
-		"user_agent":    ctx.UserAgent,
-		"remote_addr":   ctx.RemoteAddr,
-		"age":           time.Since(ctx.CreatedAt),
-		"idle_time":     time.Since(ctx.LastAccessed),
-		"is_blocked":    ctx.IsBlocked,
+		"id":                 ctx.ID,
+		"created_at":         ctx.CreatedAt,
+		"last_accessed":      ctx.LastAccessed,
+		"call_count":         atomic.LoadInt64(&ctx.CallCount),
+		"user_agent":         ctx.UserAgent,
+		"remote_addr":        ctx.RemoteAddr,
+		"age":                time.Since(ctx.CreatedAt),
+		"idle_time":          time.Since(ctx.LastAccessed),
+		"is_blocked":         ctx.IsBlocked,
+		"rate_limited_count": ctx.RateLimitedCount,
 	}
 }
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCheckRateLimit_CountsRateLimitedRequests(t *testing.T) {
+	sessionConfig := config.Default().Session
+	sessionConfig.RateLimit.RequestsPerMinute = 2
+	manager := NewManagerWithConfig(zap.NewNop(), sessionConfig)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	ctx := manager.CreateSession(map[string]string{})
+
+	// Requests within the limit are allowed and not counted as throttled
+	assert.True(t, manager.CheckRateLimit(ctx.ID))
+	assert.True(t, manager.CheckRateLimit(ctx.ID))
+	assert.Equal(t, int64(0), ctx.GetInfo()["rate_limited_count"])
+
+	// Every request over the limit is counted
+	assert.False(t, manager.CheckRateLimit(ctx.ID))
+	assert.False(t, manager.CheckRateLimit(ctx.ID))
+	assert.False(t, manager.CheckRateLimit(ctx.ID))
+	assert.Equal(t, int64(3), ctx.GetInfo()["rate_limited_count"])
+
+	// The counter is reported for active sessions
+	sessions := manager.GetActiveSessions()
+	require.Len(t, sessions, 1)
+	assert.Equal(t, ctx.ID, sessions[0]["id"])
+	assert.Equal(t, int64(3), sessions[0]["rate_limited_count"])
+}
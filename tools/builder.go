@@ -1,15 +1,20 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/lysfighting/ggRMCP/config"
 	"github.com/lysfighting/ggRMCP/mcp"
 	"github.com/lysfighting/ggRMCP/types"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// DefaultFallbackDescription describes methods without comments; {method} and {service} are substituted
+const DefaultFallbackDescription = "Calls the {method} method of the {service} service"
+
 // MCPToolBuilder builds MCP tools from gRPC service definitions and handles schema generation
 type MCPToolBuilder struct {
 	logger *zap.Logger
@@ -18,18 +23,52 @@ type MCPToolBuilder struct {
 	schemaCache map[string]interface{}
 
 	// Configuration
-	maxRecursionDepth int
-	includeComments   bool
+	maxRecursionDepth     int
+	includeComments       bool
+	shareIdenticalSchemas bool
+	emitTitles            bool
+	maxDefinitions        int
+	definitionOverflow    string
+	fallbackDescription   string
+	lazySchemas           bool
+	serverStreaming       bool
+
+	// Input schemas that replace the generated ones, keyed by tool name
+	inputSchemaOverrides map[string]map[string]interface{}
 }
 
 // NewMCPToolBuilder creates a new MCP tool builder
 func NewMCPToolBuilder(logger *zap.Logger) *MCPToolBuilder {
 	return &MCPToolBuilder{
-		logger:            logger,
-		schemaCache:       make(map[string]interface{}),
-		maxRecursionDepth: 10,
-		includeComments:   true,
+		logger:              logger,
+		schemaCache:         make(map[string]interface{}),
+		maxRecursionDepth:   10,
+		includeComments:     true,
+		maxDefinitions:      50,
+		definitionOverflow:  DefinitionOverflowTruncate,
+		fallbackDescription: DefaultFallbackDescription,
+	}
+}
+
+// NewMCPToolBuilderWithConfig creates a new MCP tool builder from tools configuration
+func NewMCPToolBuilderWithConfig(logger *zap.Logger, cfg config.ToolsConfig) *MCPToolBuilder {
+	b := NewMCPToolBuilder(logger)
+	if cfg.MaxDepth > 0 {
+		b.maxRecursionDepth = cfg.MaxDepth
 	}
+	b.shareIdenticalSchemas = cfg.ShareIdenticalSchemas
+	b.emitTitles = cfg.EmitTitles
+	if cfg.MaxDefinitions > 0 {
+		b.maxDefinitions = cfg.MaxDefinitions
+	}
+	if cfg.DefinitionOverflow != "" {
+		b.definitionOverflow = cfg.DefinitionOverflow
+	}
+	if strings.TrimSpace(cfg.FallbackDescription) != "" {
+		b.fallbackDescription = cfg.FallbackDescription
+	}
+	b.lazySchemas = cfg.LazySchemas
+	return b
 }
 
 // BuildTool builds an MCP tool from a gRPC method
@@ -40,32 +79,71 @@ func (b *MCPToolBuilder) BuildTool(method types.MethodInfo) (mcp.Tool, error) {
 	// Generate description
 	description := b.generateDescription(method)
 
-	// Generate input schema
-	b.logger.Debug("Generating input schema",
-		zap.String("toolName", toolName),
-		zap.String("inputType", string(method.InputDescriptor.FullName())))
+	// Recursive definitions emitted by the input and output schemas share one cap
+	toolDefinitions := make(map[string]bool)
+
+	// Use the configured input schema override for this tool instead of generating one
+	var inputSchema map[string]interface{}
+	var err error
+	override, overridden := b.inputSchemaOverrides[toolName]
+	if overridden {
+		b.logger.Debug("Using input schema override", zap.String("toolName", toolName))
+		inputSchema = deepCopySchema(override)
+	} else {
+		b.logger.Debug("Generating input schema",
+			zap.String("toolName", toolName),
+			zap.String("inputType", string(method.InputDescriptor.FullName())))
 
-	inputSchema, err := b.ExtractMessageSchema(method.InputDescriptor)
-	if err != nil {
-		b.logger.Error("Failed to generate input schema",
+		inputSchema, err = b.extractToolSchema(method.InputDescriptor, toolDefinitions)
+		if err != nil {
+			b.logger.Error("Failed to generate input schema",
+				zap.String("toolName", toolName),
+				zap.String("inputType", string(method.InputDescriptor.FullName())),
+				zap.Error(err))
+			return mcp.Tool{}, fmt.Errorf("failed to generate input schema: %w", err)
+		}
+	}
+
+	// Generate output schema, referencing the input schema when both use the same message
+	var outputSchema map[string]interface{}
+	if b.shareIdenticalSchemas && !overridden && method.InputDescriptor.FullName() == method.OutputDescriptor.FullName() {
+		b.logger.Debug("Sharing input schema as output schema",
+			zap.String("toolName", toolName),
+			zap.String("messageType", string(method.OutputDescriptor.FullName())))
+
+		// The input schema is emitted once under an "$id" that the output schema references
+		schemaID := sharedSchemaID(toolName)
+		inputSchema["$id"] = schemaID
+		outputSchema = map[string]interface{}{"$ref": schemaID}
+	} else {
+		b.logger.Debug("Generating output schema",
 			zap.String("toolName", toolName),
-			zap.String("inputType", string(method.InputDescriptor.FullName())),
-			zap.Error(err))
-		return mcp.Tool{}, fmt.Errorf("failed to generate input schema: %w", err)
+			zap.String("outputType", string(method.OutputDescriptor.FullName())))
+
+		outputSchema, err = b.extractToolSchema(method.OutputDescriptor, toolDefinitions)
+		if err != nil {
+			b.logger.Error("Failed to generate output schema",
+				zap.String("toolName", toolName),
+				zap.String("outputType", string(method.OutputDescriptor.FullName())),
+				zap.Error(err))
+			return mcp.Tool{}, fmt.Errorf("failed to generate output schema: %w", err)
+		}
 	}
 
-	// Generate output schema
-	b.logger.Debug("Generating output schema",
-		zap.String("toolName", toolName),
-		zap.String("outputType", string(method.OutputDescriptor.FullName())))
+	// Server-streaming responses are aggregated into a list of messages
+	if method.IsServerStreaming {
+		wrapped := map[string]interface{}{
+			"type":        "array",
+			"items":       outputSchema,
+			"description": "Messages streamed by the method, in order",
+		}
 
-	outputSchema, err := b.ExtractMessageSchema(method.OutputDescriptor)
-	if err != nil {
-		b.logger.Error("Failed to generate output schema",
-			zap.String("toolName", toolName),
-			zap.String("outputType", string(method.OutputDescriptor.FullName())),
-			zap.Error(err))
-		return mcp.Tool{}, fmt.Errorf("failed to generate output schema: %w", err)
+		// Keep definitions at the document root so "#/$defs/..." references still resolve
+		if defs, exists := outputSchema["$defs"]; exists {
+			delete(outputSchema, "$defs")
+			wrapped["$defs"] = defs
+		}
+		outputSchema = wrapped
 	}
 
 	tool := mcp.Tool{
@@ -91,12 +169,20 @@ func (b *MCPToolBuilder) BuildTool(method types.MethodInfo) (mcp.Tool, error) {
 // generateDescription generates a tool description
 func (b *MCPToolBuilder) generateDescription(method types.MethodInfo) string {
 	// Use description from method if available (could be from FileDescriptorSet comments)
-	if method.Description != "" {
-		return method.Description
+	if description := strings.TrimSpace(method.Description); !isBlankComment(description) {
+		return description
 	}
 
 	// Fallback to generic description
-	return fmt.Sprintf("Calls the %s method of the %s service", method.Name, method.ServiceName)
+	return strings.NewReplacer(
+		"{method}", method.Name,
+		"{service}", method.ServiceName,
+	).Replace(b.fallbackDescription)
+}
+
+// isBlankComment reports whether a comment holds only whitespace and comment markers
+func isBlankComment(comment string) bool {
+	return strings.Trim(comment, "/*! \t\r\n") == ""
 }
 
 // validateTool validates a generated tool
@@ -121,13 +207,26 @@ func (b *MCPToolBuilder) validateTool(tool mcp.Tool) error {
 	return nil
 }
 
+// SetServerStreaming exposes server-streaming methods as tools with aggregated results
+func (b *MCPToolBuilder) SetServerStreaming(enabled bool) {
+	b.serverStreaming = enabled
+}
+
+// IsExposed reports whether a method is built as a tool. Client and bidirectional
+// streaming methods never are; server streaming only when enabled.
+func (b *MCPToolBuilder) IsExposed(method types.MethodInfo) bool {
+	if method.IsClientStreaming {
+		return false
+	}
+	return !method.IsServerStreaming || b.serverStreaming
+}
+
 // BuildTools builds MCP tools for all methods
 func (b *MCPToolBuilder) BuildTools(methods []types.MethodInfo) ([]mcp.Tool, error) {
 	var tools []mcp.Tool
 
 	for _, method := range methods {
-		// Skip streaming methods
-		if method.IsClientStreaming || method.IsServerStreaming {
+		if !b.IsExposed(method) {
 			b.logger.Debug("Skipping streaming method",
 				zap.String("service", method.ServiceName),
 				zap.String("method", method.Name))
@@ -154,30 +253,50 @@ func (b *MCPToolBuilder) BuildTools(methods []types.MethodInfo) ([]mcp.Tool, err
 
 // ExtractMessageSchema generates a JSON schema for a message with comments
 func (b *MCPToolBuilder) ExtractMessageSchema(msgDesc protoreflect.MessageDescriptor) (map[string]interface{}, error) {
-	// Use internal method with visited tracking
-	return b.extractMessageSchemaInternal(msgDesc, make(map[string]bool))
+	return b.extractToolSchema(msgDesc, make(map[string]bool))
+}
+
+// extractToolSchema generates one schema document of a tool, counting its definitions
+// against the definitions already emitted for the tool
+func (b *MCPToolBuilder) extractToolSchema(msgDesc protoreflect.MessageDescriptor, toolDefinitions map[string]bool) (map[string]interface{}, error) {
+	state := newSchemaState(toolDefinitions)
+	state.lazy = b.lazySchemas
+	schema, err := b.extractMessageSchemaInternal(msgDesc, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit definitions for the recursive messages referenced via $ref
+	if err := b.addDefinitions(schema, state); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
 }
 
 // extractMessageSchemaInternal generates a JSON schema with circular reference detection
-func (b *MCPToolBuilder) extractMessageSchemaInternal(msgDesc protoreflect.MessageDescriptor, visited map[string]bool) (map[string]interface{}, error) {
+func (b *MCPToolBuilder) extractMessageSchemaInternal(msgDesc protoreflect.MessageDescriptor, state *schemaState) (map[string]interface{}, error) {
 	// Check for circular references
 	fullName := string(msgDesc.FullName())
-	if visited[fullName] {
+	if state.visited[fullName] {
 		// Return a reference to break the cycle
 		b.logger.Debug("Found circular reference, using $ref",
 			zap.String("messageType", fullName))
-		return map[string]interface{}{
-			"$ref": "#/definitions/" + fullName,
-		}, nil
+		return b.definitionRef(msgDesc, state)
 	}
-	visited[fullName] = true
-	defer func() { delete(visited, fullName) }() // Clean up on exit
+	state.visited[fullName] = true
+	defer func() { delete(state.visited, fullName) }() // Clean up on exit
 
 	schema := map[string]interface{}{
 		"type":       "object",
 		"properties": make(map[string]interface{}),
 	}
 
+	// Title object schemas with the message's short name (e.g. UserProfile)
+	if b.emitTitles {
+		schema["title"] = string(msgDesc.Name())
+	}
+
 	// Add message-level description if available
 	if desc := b.extractComments(msgDesc); desc != "" {
 		schema["description"] = desc
@@ -191,7 +310,10 @@ func (b *MCPToolBuilder) extractMessageSchemaInternal(msgDesc protoreflect.Messa
 		field := msgDesc.Fields().Get(i)
 		fieldName := string(field.Name())
 
-		fieldSchema, err := b.extractFieldSchemaInternal(field, visited)
+		fieldSchema, err := b.extractFieldSchemaInternal(field, state)
+		if errors.Is(err, ErrDefinitionLimitExceeded) {
+			return nil, err
+		}
 		if err != nil {
 			b.logger.Warn("Failed to extract field schema",
 				zap.String("message", string(msgDesc.FullName())),
@@ -230,7 +352,10 @@ func (b *MCPToolBuilder) extractMessageSchemaInternal(msgDesc protoreflect.Messa
 			field := oneof.Fields().Get(j)
 			fieldName := string(field.Name())
 
-			fieldSchema, err := b.extractFieldSchemaInternal(field, visited)
+			fieldSchema, err := b.extractFieldSchemaInternal(field, state)
+			if errors.Is(err, ErrDefinitionLimitExceeded) {
+				return nil, err
+			}
 			if err != nil {
 				b.logger.Warn("Failed to extract field schema for oneof",
 					zap.String("field", fieldName),
@@ -256,11 +381,40 @@ func (b *MCPToolBuilder) extractMessageSchemaInternal(msgDesc protoreflect.Messa
 		schema["required"] = required
 	}
 
+	// Properties is an unordered map, so record the proto declaration order separately
+	if fieldOrder := declarationOrder(msgDesc, properties); len(fieldOrder) > 0 {
+		schema["x-field-order"] = fieldOrder
+	}
+
 	return schema, nil
 }
 
+// declarationOrder lists the generated properties in proto declaration order; a oneof is
+// placed just before its first member and synthetic proto3 optional oneofs are skipped
+func declarationOrder(msgDesc protoreflect.MessageDescriptor, properties map[string]interface{}) []string {
+	order := []string{}
+	placedOneofs := make(map[protoreflect.Name]bool)
+
+	for i := 0; i < msgDesc.Fields().Len(); i++ {
+		field := msgDesc.Fields().Get(i)
+
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() && !placedOneofs[oneof.Name()] {
+			placedOneofs[oneof.Name()] = true
+			if _, exists := properties[string(oneof.Name())]; exists {
+				order = append(order, string(oneof.Name()))
+			}
+		}
+
+		if _, exists := properties[string(field.Name())]; exists {
+			order = append(order, string(field.Name()))
+		}
+	}
+
+	return order
+}
+
 // extractFieldSchemaInternal generates schema for a single field with circular reference detection
-func (b *MCPToolBuilder) extractFieldSchemaInternal(field protoreflect.FieldDescriptor, visited map[string]bool) (map[string]interface{}, error) {
+func (b *MCPToolBuilder) extractFieldSchemaInternal(field protoreflect.FieldDescriptor, state *schemaState) (map[string]interface{}, error) {
 	schema := make(map[string]interface{})
 
 	// Add field description if available
@@ -270,7 +424,7 @@ func (b *MCPToolBuilder) extractFieldSchemaInternal(field protoreflect.FieldDesc
 
 	// Handle repeated fields
 	if field.IsList() {
-		itemSchema, err := b.extractFieldTypeSchemaInternal(field, visited)
+		itemSchema, err := b.extractFieldTypeSchemaInternal(field, state)
 		if err != nil {
 			return nil, err
 		}
@@ -283,7 +437,7 @@ func (b *MCPToolBuilder) extractFieldSchemaInternal(field protoreflect.FieldDesc
 	// Handle map fields
 	if field.IsMap() {
 		valueField := field.MapValue()
-		valueSchema, err := b.extractFieldTypeSchemaInternal(valueField, visited)
+		valueSchema, err := b.extractFieldTypeSchemaInternal(valueField, state)
 		if err != nil {
 			return nil, err
 		}
@@ -297,11 +451,11 @@ func (b *MCPToolBuilder) extractFieldSchemaInternal(field protoreflect.FieldDesc
 	}
 
 	// Handle regular fields
-	return b.extractFieldTypeSchemaInternal(field, visited)
+	return b.extractFieldTypeSchemaInternal(field, state)
 }
 
 // extractFieldTypeSchemaInternal generates schema for the field's type with circular reference detection
-func (b *MCPToolBuilder) extractFieldTypeSchemaInternal(field protoreflect.FieldDescriptor, visited map[string]bool) (map[string]interface{}, error) {
+func (b *MCPToolBuilder) extractFieldTypeSchemaInternal(field protoreflect.FieldDescriptor, state *schemaState) (map[string]interface{}, error) {
 	schema := make(map[string]interface{})
 
 	switch field.Kind() {
@@ -418,8 +572,13 @@ func (b *MCPToolBuilder) extractFieldTypeSchemaInternal(field protoreflect.Field
 			schema["type"] = "number"
 
 		default:
+			// In lazy mode nested messages are resolved on demand via their schema URI
+			if state.lazy {
+				return lazyRef(msgDesc), nil
+			}
+
 			// Custom message type - extract schema recursively
-			messageSchema, err := b.extractMessageSchemaInternal(msgDesc, visited)
+			messageSchema, err := b.extractMessageSchemaInternal(msgDesc, state)
 			if err != nil {
 				return nil, fmt.Errorf("failed to extract schema for message %s: %w", msgDesc.FullName(), err)
 			}
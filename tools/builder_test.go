@@ -1,14 +1,20 @@
 package tools
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/mcp"
 	"github.com/lysfighting/ggRMCP/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 func TestBuildTool_RecursiveTypes(t *testing.T) {
@@ -324,3 +330,227 @@ func TestBuildTools_MultipleServices(t *testing.T) {
 	assert.True(t, toolNames["com_example_complex_documentservice_createdocument"], "Should include DocumentService tool")
 	assert.True(t, toolNames["com_example_complex_nodeservice_processnode"], "Should include NodeService tool")
 }
+
+func TestBuildTool_SharedInputOutputSchema(t *testing.T) {
+	logger := zap.NewNop()
+
+	toolsConfig := config.Default().Tools
+	toolsConfig.ShareIdenticalSchemas = true
+	builder := NewMCPToolBuilderWithConfig(logger, toolsConfig)
+
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("shared.proto"),
+		Package: proto.String("test.shared"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Document"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					stringField("id", 1),
+					stringField("body", 2),
+					stringField("title", 3),
+					stringField("author", 4),
+					stringField("language", 5),
+					stringField("revision", 6),
+				},
+			},
+		},
+	})
+	documentDesc := fd.Messages().ByName("Document")
+
+	methodInfo := types.MethodInfo{
+		Name:             "Normalize",
+		FullName:         "test.shared.DocumentService.Normalize",
+		ServiceName:      "test.shared.DocumentService",
+		InputDescriptor:  documentDesc,
+		OutputDescriptor: documentDesc,
+	}
+
+	tool, err := builder.BuildTool(methodInfo)
+	require.NoError(t, err)
+
+	inputSchema, ok := tool.InputSchema.(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, inputSchema["properties"], "id")
+
+	// The output schema only references the input schema by its $id
+	outputSchema, ok := tool.OutputSchema.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"$ref": inputSchema["$id"]}, outputSchema)
+	assert.Equal(t, "urn:ggrmcp:tools:"+methodInfo.GenerateToolName()+":input", inputSchema["$id"])
+
+	// Without the option both schemas are generated in full, making the tool larger
+	unshared, err := NewMCPToolBuilder(logger).BuildTool(methodInfo)
+	require.NoError(t, err)
+	assert.Equal(t, unshared.InputSchema, unshared.OutputSchema)
+
+	sharedJSON, err := json.Marshal(tool)
+	require.NoError(t, err)
+	unsharedJSON, err := json.Marshal(unshared)
+	require.NoError(t, err)
+	assert.Less(t, len(sharedJSON), len(unsharedJSON))
+}
+
+func TestExtractMessageSchema_FieldOrder(t *testing.T) {
+	builder := NewMCPToolBuilder(zap.NewNop())
+
+	// Declaration order differs from both alphabetical and field number order
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("ordered.proto"),
+		Package: proto.String("test.ordered"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Form"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					stringField("zeta", 3),
+					stringField("alpha", 1),
+					oneofField(stringField("email", 5), 0),
+					oneofField(stringField("phone", 6), 0),
+					stringField("middle", 4),
+					proto3OptionalField(stringField("nickname", 7), 1),
+					stringField("beta", 2),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{
+					{Name: proto.String("contact")},
+					{Name: proto.String("_nickname")},
+				},
+			},
+		},
+	})
+
+	schema, err := builder.ExtractMessageSchema(fd.Messages().ByName("Form"))
+	require.NoError(t, err)
+
+	// The oneof sits at its first member's position and the synthetic "_nickname" oneof is not listed
+	assert.Equal(t, []string{"zeta", "alpha", "contact", "email", "phone", "middle", "nickname", "beta"}, schema["x-field-order"])
+}
+
+// oneofField places a field in the oneof declared at the given index
+func oneofField(field *descriptorpb.FieldDescriptorProto, oneofIndex int32) *descriptorpb.FieldDescriptorProto {
+	field.OneofIndex = proto.Int32(oneofIndex)
+	return field
+}
+
+// proto3OptionalField marks a field as proto3 optional, backed by the synthetic oneof at the given index
+func proto3OptionalField(field *descriptorpb.FieldDescriptorProto, oneofIndex int32) *descriptorpb.FieldDescriptorProto {
+	field.Proto3Optional = proto.Bool(true)
+	return oneofField(field, oneofIndex)
+}
+
+func TestExtractMessageSchema_Titles(t *testing.T) {
+	toolsConfig := config.Default().Tools
+	toolsConfig.EmitTitles = true
+	builder := NewMCPToolBuilderWithConfig(zap.NewNop(), toolsConfig)
+
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("titled.proto"),
+		Package: proto.String("test.titled"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("UserProfile"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					stringField("name", 1),
+					{
+						Name:     proto.String("address"),
+						Number:   proto.Int32(2),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+						TypeName: proto.String(".test.titled.UserProfile.Address"),
+					},
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name:  proto.String("Address"),
+						Field: []*descriptorpb.FieldDescriptorProto{stringField("city", 1)},
+					},
+				},
+			},
+		},
+	})
+	profileDesc := fd.Messages().ByName("UserProfile")
+
+	schema, err := builder.ExtractMessageSchema(profileDesc)
+	require.NoError(t, err)
+	assert.Equal(t, "UserProfile", schema["title"])
+
+	// Nested types use their short name rather than the qualified one
+	properties := schema["properties"].(map[string]interface{})
+	addressSchema := properties["address"].(map[string]interface{})
+	assert.Equal(t, "Address", addressSchema["title"])
+	assert.NotContains(t, properties["name"], "title")
+
+	// Titles are omitted unless enabled
+	schema, err = NewMCPToolBuilder(zap.NewNop()).ExtractMessageSchema(profileDesc)
+	require.NoError(t, err)
+	assert.NotContains(t, schema, "title")
+}
+
+// buildTestFile builds a file descriptor from an inline proto definition
+func buildTestFile(t *testing.T, fdProto *descriptorpb.FileDescriptorProto) protoreflect.FileDescriptor {
+	t.Helper()
+	fd, err := protodesc.NewFile(fdProto, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return fd
+}
+
+// stringField creates a proto3 string field descriptor
+func stringField(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	}
+}
+
+func TestBuildTools_ServerStreamingOptIn(t *testing.T) {
+	msgDesc := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("streaming_opt_in.proto"),
+		Package: proto.String("test.optin"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Event"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("id", 1)},
+			},
+		},
+	}).Messages().ByName("Event")
+
+	method := func(name string, clientStreaming, serverStreaming bool) types.MethodInfo {
+		return types.MethodInfo{
+			Name:              name,
+			FullName:          "test.optin.EventService." + name,
+			ServiceName:       "test.optin.EventService",
+			InputDescriptor:   msgDesc,
+			OutputDescriptor:  msgDesc,
+			IsClientStreaming: clientStreaming,
+			IsServerStreaming: serverStreaming,
+		}
+	}
+	methods := []types.MethodInfo{
+		method("GetEvent", false, false),
+		method("WatchEvents", false, true),
+		method("SyncEvents", true, true),
+	}
+
+	toolNames := func(tools []mcp.Tool) []string {
+		names := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	// Server streaming is skipped unless enabled
+	builder := NewMCPToolBuilder(zap.NewNop())
+	tools, err := builder.BuildTools(methods)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test_optin_eventservice_getevent"}, toolNames(tools))
+
+	builder.SetServerStreaming(true)
+	tools, err = builder.BuildTools(methods)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test_optin_eventservice_getevent", "test_optin_eventservice_watchevents"}, toolNames(tools))
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"sort"
+
+	"github.com/lysfighting/ggRMCP/mcp"
+	"github.com/lysfighting/ggRMCP/types"
+)
+
+// ServiceCatalogToolName is the name of the built-in service catalog meta-tool
+const ServiceCatalogToolName = "__list_services"
+
+// ServiceCatalog describes the discovered services and their methods
+type ServiceCatalog struct {
+	Services []CatalogService `json:"services"`
+}
+
+// CatalogService describes a single discovered service
+type CatalogService struct {
+	Name    string          `json:"name"`
+	Package string          `json:"package,omitempty"`
+	File    string          `json:"file,omitempty"`
+	Methods []CatalogMethod `json:"methods"`
+}
+
+// CatalogMethod describes a single method of a discovered service
+type CatalogMethod struct {
+	Name string `json:"name"`
+
+	// Tool is empty for methods not exposed as tools (e.g. client streaming)
+	Tool string `json:"tool,omitempty"`
+
+	// Enabled reports whether the tool can currently be listed and called
+	Enabled bool `json:"enabled"`
+
+	Description     string `json:"description,omitempty"`
+	InputType       string `json:"inputType"`
+	OutputType      string `json:"outputType"`
+	ClientStreaming bool   `json:"clientStreaming"`
+	ServerStreaming bool   `json:"serverStreaming"`
+}
+
+// ServiceCatalogTool returns the definition of the service catalog meta-tool
+func ServiceCatalogTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        ServiceCatalogToolName,
+		Description: "Returns the catalog of discovered gRPC services and their methods",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	}
+}
+
+// BuildServiceCatalog groups methods by service, sorted by service and method name.
+// isEnabled reports whether an exposed tool is currently enabled.
+func (b *MCPToolBuilder) BuildServiceCatalog(methods []types.MethodInfo, isEnabled func(toolName string) bool) ServiceCatalog {
+	services := make(map[string]*CatalogService)
+	for _, method := range methods {
+		service, exists := services[method.ServiceName]
+		if !exists {
+			service = &CatalogService{
+				Name:    method.ServiceName,
+				Package: method.PackageName,
+				File:    method.FileName,
+			}
+			services[method.ServiceName] = service
+		}
+
+		catalogMethod := CatalogMethod{
+			Name:            method.Name,
+			Description:     method.Description,
+			ClientStreaming: method.IsClientStreaming,
+			ServerStreaming: method.IsServerStreaming,
+		}
+		if b.IsExposed(method) {
+			catalogMethod.Tool = method.GenerateToolName()
+			catalogMethod.Enabled = isEnabled(catalogMethod.Tool)
+		}
+		if method.InputDescriptor != nil {
+			catalogMethod.InputType = string(method.InputDescriptor.FullName())
+		}
+		if method.OutputDescriptor != nil {
+			catalogMethod.OutputType = string(method.OutputDescriptor.FullName())
+		}
+		service.Methods = append(service.Methods, catalogMethod)
+	}
+
+	catalog := ServiceCatalog{Services: make([]CatalogService, 0, len(services))}
+	for _, service := range services {
+		sort.Slice(service.Methods, func(i, j int) bool {
+			return service.Methods[i].Name < service.Methods[j].Name
+		})
+		catalog.Services = append(catalog.Services, *service)
+	}
+	sort.Slice(catalog.Services, func(i, j int) bool {
+		return catalog.Services[i].Name < catalog.Services[j].Name
+	})
+
+	return catalog
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Policies applied when a schema needs more recursive definitions than allowed
+const (
+	// DefinitionOverflowTruncate replaces further recursive references with a generic object
+	DefinitionOverflowTruncate = "truncate"
+
+	// DefinitionOverflowError fails schema generation, so the tool is skipped
+	DefinitionOverflowError = "error"
+)
+
+// ErrDefinitionLimitExceeded is returned when a schema exceeds the definition cap under the error policy
+var ErrDefinitionLimitExceeded = errors.New("schema definition limit exceeded")
+
+// schemaState tracks recursion and referenced definitions while generating one schema document
+type schemaState struct {
+	// Messages on the current path, used to detect cycles
+	visited map[string]bool
+
+	// Recursive messages referenced via "#/$defs/<name>", in the order first referenced
+	definitions     map[string]protoreflect.MessageDescriptor
+	definitionOrder []string
+
+	// Definitions emitted by any schema of the same tool, shared so the cap applies per tool
+	toolDefinitions map[string]bool
+
+	// Emit nested messages as lazy "schema://" placeholders instead of inlining them
+	lazy bool
+}
+
+// newSchemaState creates the state for a new schema document of a tool
+func newSchemaState(toolDefinitions map[string]bool) *schemaState {
+	return &schemaState{
+		visited:         make(map[string]bool),
+		definitions:     make(map[string]protoreflect.MessageDescriptor),
+		toolDefinitions: toolDefinitions,
+	}
+}
+
+// definitionRef returns a $ref to the definition of a recursive message, registering the
+// definition if needed and applying the overflow policy once the cap is reached
+func (b *MCPToolBuilder) definitionRef(msgDesc protoreflect.MessageDescriptor, state *schemaState) (map[string]interface{}, error) {
+	fullName := string(msgDesc.FullName())
+
+	if _, exists := state.definitions[fullName]; !exists {
+		if b.maxDefinitions > 0 && !state.toolDefinitions[fullName] && len(state.toolDefinitions) >= b.maxDefinitions {
+			if b.definitionOverflow == DefinitionOverflowError {
+				return nil, fmt.Errorf("%w: %s would exceed %d definitions",
+					ErrDefinitionLimitExceeded, fullName, b.maxDefinitions)
+			}
+
+			b.logger.Debug("Definition limit reached, truncating recursive reference",
+				zap.String("messageType", fullName),
+				zap.Int("maxDefinitions", b.maxDefinitions))
+			return map[string]interface{}{
+				"type":        "object",
+				"description": fmt.Sprintf("Recursive %s omitted: definition limit reached", fullName),
+			}, nil
+		}
+
+		state.definitions[fullName] = msgDesc
+		state.definitionOrder = append(state.definitionOrder, fullName)
+		state.toolDefinitions[fullName] = true
+	}
+
+	return map[string]interface{}{
+		"$ref": "#/$defs/" + fullName,
+	}, nil
+}
+
+// sharedSchemaID returns the "$id" under which a tool's input schema is shared with its
+// output schema
+func sharedSchemaID(toolName string) string {
+	return "urn:ggrmcp:tools:" + toolName + ":input"
+}
+
+// addDefinitions generates the schemas of all referenced recursive messages into "$defs"
+func (b *MCPToolBuilder) addDefinitions(schema map[string]interface{}, state *schemaState) error {
+	if len(state.definitionOrder) == 0 {
+		return nil
+	}
+
+	defs := make(map[string]interface{}, len(state.definitionOrder))
+
+	// Generating a definition can reference further messages, growing the list
+	for i := 0; i < len(state.definitionOrder); i++ {
+		fullName := state.definitionOrder[i]
+
+		definition, err := b.extractMessageSchemaInternal(state.definitions[fullName], state)
+		if err != nil {
+			return fmt.Errorf("failed to generate definition for %s: %w", fullName, err)
+		}
+		defs[fullName] = definition
+	}
+
+	schema["$defs"] = defs
+	return nil
+}
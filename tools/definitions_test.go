@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// newMutuallyRecursiveMessage builds messages A-D where every message references all four
+func newMutuallyRecursiveMessage(t *testing.T) protoreflect.MessageDescriptor {
+	return newRecursiveGraph(t, "test.graph")
+}
+
+// newRecursiveGraph builds mutually recursive messages A-D in the given package and returns A
+func newRecursiveGraph(t *testing.T, pkg string) protoreflect.MessageDescriptor {
+	names := []string{"A", "B", "C", "D"}
+
+	var messages []*descriptorpb.DescriptorProto
+	for _, name := range names {
+		message := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+		for i, target := range names {
+			message.Field = append(message.Field, &descriptorpb.FieldDescriptorProto{
+				Name:     proto.String(strings.ToLower(target)),
+				Number:   proto.Int32(int32(i + 1)),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: proto.String("." + pkg + "." + target),
+			})
+		}
+		messages = append(messages, message)
+	}
+
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:        proto.String(strings.ReplaceAll(pkg, ".", "/") + ".proto"),
+		Package:     proto.String(pkg),
+		Syntax:      proto.String("proto3"),
+		MessageType: messages,
+	})
+	return fd.Messages().ByName("A")
+}
+
+// collectRefs returns every $ref value found in a schema
+func collectRefs(schema interface{}) []string {
+	var refs []string
+	switch node := schema.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, collectRefs(value)...)
+		}
+	case []interface{}:
+		for _, value := range node {
+			refs = append(refs, collectRefs(value)...)
+		}
+	}
+	return refs
+}
+
+func newDefinitionsBuilder(maxDefinitions int, overflow string) *MCPToolBuilder {
+	toolsConfig := config.Default().Tools
+	toolsConfig.MaxDefinitions = maxDefinitions
+	toolsConfig.DefinitionOverflow = overflow
+	return NewMCPToolBuilderWithConfig(zap.NewNop(), toolsConfig)
+}
+
+func TestExtractMessageSchema_RecursiveDefinitions(t *testing.T) {
+	builder := newDefinitionsBuilder(10, DefinitionOverflowTruncate)
+
+	schema, err := builder.ExtractMessageSchema(newMutuallyRecursiveMessage(t))
+	require.NoError(t, err)
+
+	defs, ok := schema["$defs"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Len(t, defs, 4)
+
+	// Every reference resolves to an emitted definition
+	refs := collectRefs(schema)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		require.True(t, strings.HasPrefix(ref, "#/$defs/"), ref)
+		assert.Contains(t, defs, strings.TrimPrefix(ref, "#/$defs/"))
+	}
+}
+
+func TestExtractMessageSchema_DefinitionCap(t *testing.T) {
+	rootDesc := newMutuallyRecursiveMessage(t)
+
+	t.Run("truncate keeps the first definitions and omits the rest", func(t *testing.T) {
+		builder := newDefinitionsBuilder(2, DefinitionOverflowTruncate)
+
+		schema, err := builder.ExtractMessageSchema(rootDesc)
+		require.NoError(t, err)
+
+		defs := schema["$defs"].(map[string]interface{})
+		assert.Len(t, defs, 2)
+		assert.Contains(t, defs, "test.graph.A")
+		assert.Contains(t, defs, "test.graph.B")
+
+		for _, ref := range collectRefs(schema) {
+			assert.Contains(t, defs, strings.TrimPrefix(ref, "#/$defs/"))
+		}
+
+		// References past the cap are replaced by a placeholder object
+		properties := schema["properties"].(map[string]interface{})
+		cSchema := properties["b"].(map[string]interface{})["properties"].(map[string]interface{})["c"].(map[string]interface{})
+		dSchema := cSchema["properties"].(map[string]interface{})["d"].(map[string]interface{})
+		cRef := dSchema["properties"].(map[string]interface{})["c"].(map[string]interface{})
+		assert.Equal(t, "object", cRef["type"])
+		assert.Contains(t, cRef["description"], "definition limit reached")
+	})
+
+	t.Run("error policy fails the schema", func(t *testing.T) {
+		builder := newDefinitionsBuilder(2, DefinitionOverflowError)
+
+		_, err := builder.ExtractMessageSchema(rootDesc)
+		assert.ErrorIs(t, err, ErrDefinitionLimitExceeded)
+	})
+}
+
+func TestBuildTool_DefinitionCapPerTool(t *testing.T) {
+	// Input and output each need 4 definitions; together they exceed the cap of 6
+	method := types.MethodInfo{
+		Name:             "Walk",
+		FullName:         "test.graph.GraphService.Walk",
+		ServiceName:      "test.graph.GraphService",
+		InputDescriptor:  newRecursiveGraph(t, "test.graph.in"),
+		OutputDescriptor: newRecursiveGraph(t, "test.graph.out"),
+	}
+
+	t.Run("truncate shares the cap between input and output", func(t *testing.T) {
+		tool, err := newDefinitionsBuilder(6, DefinitionOverflowTruncate).BuildTool(method)
+		require.NoError(t, err)
+
+		inputDefs := tool.InputSchema.(map[string]interface{})["$defs"].(map[string]interface{})
+		outputDefs := tool.OutputSchema.(map[string]interface{})["$defs"].(map[string]interface{})
+		assert.Len(t, inputDefs, 4)
+		assert.Len(t, outputDefs, 2)
+	})
+
+	t.Run("error policy skips the tool", func(t *testing.T) {
+		_, err := newDefinitionsBuilder(6, DefinitionOverflowError).BuildTool(method)
+		assert.ErrorIs(t, err, ErrDefinitionLimitExceeded)
+
+		// Each schema alone fits within the cap
+		builder := newDefinitionsBuilder(6, DefinitionOverflowError)
+		_, err = builder.ExtractMessageSchema(method.InputDescriptor)
+		assert.NoError(t, err)
+		_, err = builder.ExtractMessageSchema(method.OutputDescriptor)
+		assert.NoError(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"testing"
 
+	"github.com/lysfighting/ggRMCP/config"
 	"github.com/lysfighting/ggRMCP/grpc"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -41,3 +42,41 @@ func TestGenerateDescription_WithoutComments(t *testing.T) {
 	// Verify fallback description
 	assert.Equal(t, "Calls the SayHello method of the hello.HelloService service", description)
 }
+
+func TestGenerateDescription_BlankComments(t *testing.T) {
+	builder := NewMCPToolBuilder(zap.NewNop())
+
+	for _, comment := range []string{"   ", "\n\t\n", "//", "/* */", " * "} {
+		methodInfo := grpc.MethodInfo{
+			Name:        "SayHello",
+			ServiceName: "hello.HelloService",
+			Description: comment,
+		}
+
+		// Whitespace or marker-only comments fall back to the generic sentence
+		assert.Equal(t, "Calls the SayHello method of the hello.HelloService service",
+			builder.generateDescription(methodInfo), "comment %q", comment)
+	}
+
+	// Real comments are kept, minus surrounding whitespace
+	methodInfo := grpc.MethodInfo{
+		Name:        "SayHello",
+		ServiceName: "hello.HelloService",
+		Description: "  Sends a greeting  \n",
+	}
+	assert.Equal(t, "Sends a greeting", builder.generateDescription(methodInfo))
+}
+
+func TestGenerateDescription_ConfiguredFallback(t *testing.T) {
+	toolsConfig := config.Default().Tools
+	toolsConfig.FallbackDescription = "Invoke {service}/{method}"
+	builder := NewMCPToolBuilderWithConfig(zap.NewNop(), toolsConfig)
+
+	methodInfo := grpc.MethodInfo{
+		Name:        "SayHello",
+		ServiceName: "hello.HelloService",
+		Description: " \n ",
+	}
+
+	assert.Equal(t, "Invoke hello.HelloService/SayHello", builder.generateDescription(methodInfo))
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lysfighting/ggRMCP/types"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SchemaURIScheme prefixes the lazy $ref placeholders of nested messages, e.g. "schema://pkg.Message"
+const SchemaURIScheme = "schema://"
+
+// SchemaURI returns the placeholder URI resolving to a message's schema
+func SchemaURI(fullName protoreflect.FullName) string {
+	return SchemaURIScheme + string(fullName)
+}
+
+// lazyRef returns the placeholder emitted for a nested message in lazy mode
+func lazyRef(msgDesc protoreflect.MessageDescriptor) map[string]interface{} {
+	return map[string]interface{}{
+		"$ref": SchemaURI(msgDesc.FullName()),
+	}
+}
+
+// ResolveSchemaURI generates the schema behind a lazy placeholder URI, looking the
+// message up among the types reachable from the methods exposed as tools
+func (b *MCPToolBuilder) ResolveSchemaURI(uri string, methods []types.MethodInfo) (map[string]interface{}, error) {
+	fullName, ok := strings.CutPrefix(uri, SchemaURIScheme)
+	if !ok || fullName == "" {
+		return nil, fmt.Errorf("invalid schema URI %q", uri)
+	}
+
+	exposed := make([]types.MethodInfo, 0, len(methods))
+	for _, method := range methods {
+		if b.IsExposed(method) {
+			exposed = append(exposed, method)
+		}
+	}
+
+	msgDesc := findMessage(protoreflect.FullName(fullName), exposed)
+	if msgDesc == nil {
+		return nil, fmt.Errorf("schema %s not found", fullName)
+	}
+
+	return b.ExtractMessageSchema(msgDesc)
+}
+
+// findMessage searches the input and output types of the methods, and the messages they reference
+func findMessage(fullName protoreflect.FullName, methods []types.MethodInfo) protoreflect.MessageDescriptor {
+	seen := make(map[protoreflect.FullName]bool)
+	var queue []protoreflect.MessageDescriptor
+	for _, method := range methods {
+		queue = append(queue, method.InputDescriptor, method.OutputDescriptor)
+	}
+
+	for len(queue) > 0 {
+		msgDesc := queue[0]
+		queue = queue[1:]
+		if msgDesc == nil || seen[msgDesc.FullName()] {
+			continue
+		}
+		seen[msgDesc.FullName()] = true
+
+		if msgDesc.FullName() == fullName {
+			return msgDesc
+		}
+
+		fields := msgDesc.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if nested := fields.Get(i).Message(); nested != nil {
+				queue = append(queue, nested)
+			}
+		}
+	}
+
+	return nil
+}
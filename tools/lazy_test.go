@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/config"
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// messageField creates a proto3 field of the given message type
+func messageField(name string, number int32, typeName string, repeated bool) *descriptorpb.FieldDescriptorProto {
+	label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	if repeated {
+		label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	}
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    label.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: proto.String(typeName),
+	}
+}
+
+func TestBuildTool_LazySchemas(t *testing.T) {
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("lazy.proto"),
+		Package: proto.String("test.lazy"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Order"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					stringField("id", 1),
+					messageField("customer", 2, ".test.lazy.Customer", false),
+					messageField("items", 3, ".test.lazy.Item", true),
+				},
+			},
+			{
+				Name: proto.String("Customer"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					stringField("name", 1),
+					messageField("address", 2, ".test.lazy.Address", false),
+				},
+			},
+			{
+				Name:  proto.String("Item"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("sku", 1)},
+			},
+			{
+				Name:  proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("city", 1)},
+			},
+		},
+	})
+	orderDesc := fd.Messages().ByName("Order")
+
+	cfg := config.Default().Tools
+	cfg.LazySchemas = true
+	builder := NewMCPToolBuilderWithConfig(zap.NewNop(), cfg)
+
+	method := types.MethodInfo{
+		Name:             "PlaceOrder",
+		FullName:         "test.lazy.OrderService.PlaceOrder",
+		ServiceName:      "test.lazy.OrderService",
+		InputDescriptor:  orderDesc,
+		OutputDescriptor: orderDesc,
+	}
+
+	tool, err := builder.BuildTool(method)
+	require.NoError(t, err)
+
+	// Only top-level fields are generated; nested messages are placeholders
+	inputSchema := tool.InputSchema.(map[string]interface{})
+	properties := inputSchema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["id"])
+	assert.Equal(t, map[string]interface{}{"$ref": "schema://test.lazy.Customer"}, properties["customer"])
+	items := properties["items"].(map[string]interface{})
+	assert.Equal(t, "array", items["type"])
+	assert.Equal(t, map[string]interface{}{"$ref": "schema://test.lazy.Item"}, items["items"])
+	assert.NotContains(t, inputSchema, "$defs")
+
+	// Placeholders resolve one level at a time
+	customer, err := builder.ResolveSchemaURI("schema://test.lazy.Customer", []types.MethodInfo{method})
+	require.NoError(t, err)
+	customerProps := customer["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, customerProps["name"])
+	assert.Equal(t, map[string]interface{}{"$ref": "schema://test.lazy.Address"}, customerProps["address"])
+
+	address, err := builder.ResolveSchemaURI("schema://test.lazy.Address", []types.MethodInfo{method})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"city"}, address["x-field-order"])
+
+	// Unknown and malformed URIs are rejected
+	_, err = builder.ResolveSchemaURI("schema://test.lazy.Missing", []types.MethodInfo{method})
+	assert.Error(t, err)
+	_, err = builder.ResolveSchemaURI("file:///etc/passwd", []types.MethodInfo{method})
+	assert.Error(t, err)
+}
+
+func TestResolveSchemaURI_OnlyExposedMethods(t *testing.T) {
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("lazy_exposed.proto"),
+		Package: proto.String("test.lazyexposed"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Request"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("id", 1)},
+			},
+			{
+				Name: proto.String("Event"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					messageField("detail", 1, ".test.lazyexposed.Detail", false),
+				},
+			},
+			{
+				Name:  proto.String("Detail"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("note", 1)},
+			},
+		},
+	})
+
+	cfg := config.Default().Tools
+	cfg.LazySchemas = true
+	builder := NewMCPToolBuilderWithConfig(zap.NewNop(), cfg)
+
+	// Detail is only reachable from a server-streaming method, which is not a tool by default
+	methods := []types.MethodInfo{
+		{
+			Name:             "Get",
+			FullName:         "test.lazyexposed.Service.Get",
+			ServiceName:      "test.lazyexposed.Service",
+			InputDescriptor:  fd.Messages().ByName("Request"),
+			OutputDescriptor: fd.Messages().ByName("Request"),
+		},
+		{
+			Name:              "Watch",
+			FullName:          "test.lazyexposed.Service.Watch",
+			ServiceName:       "test.lazyexposed.Service",
+			InputDescriptor:   fd.Messages().ByName("Request"),
+			OutputDescriptor:  fd.Messages().ByName("Event"),
+			IsServerStreaming: true,
+		},
+	}
+
+	_, err := builder.ResolveSchemaURI("schema://test.lazyexposed.Detail", methods)
+	assert.Error(t, err)
+
+	builder.SetServerStreaming(true)
+	detail, err := builder.ResolveSchemaURI("schema://test.lazyexposed.Detail", methods)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"note"}, detail["x-field-order"])
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadInputSchemaOverrides loads a JSON file mapping tool names to the input schema
+// that replaces the generated one, e.g. {"user_service_getuser": {"type": "object", ...}}
+func LoadInputSchemaOverrides(path string) (map[string]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema override file %s: %w", path, err)
+	}
+
+	var overrides map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse schema override file %s: %w", path, err)
+	}
+
+	for toolName, schema := range overrides {
+		if schema == nil {
+			return nil, fmt.Errorf("schema override for tool %s must be a JSON object", toolName)
+		}
+	}
+
+	return overrides, nil
+}
+
+// SetInputSchemaOverrides replaces the generated input schema of the named tools.
+// Invocation still uses the method's real descriptors. The overrides are copied.
+func (b *MCPToolBuilder) SetInputSchemaOverrides(overrides map[string]map[string]interface{}) {
+	copied := make(map[string]map[string]interface{}, len(overrides))
+	for toolName, schema := range overrides {
+		copied[toolName] = deepCopySchema(schema)
+	}
+	b.inputSchemaOverrides = copied
+}
+
+// deepCopySchema copies a JSON schema so that neither side sees later changes to the other
+func deepCopySchema(schema map[string]interface{}) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	return deepCopyValue(schema).(map[string]interface{})
+}
+
+// deepCopyValue copies the maps and slices of a decoded JSON value
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, nested := range v {
+			copied[key] = deepCopyValue(nested)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, nested := range v {
+			copied[i] = deepCopyValue(nested)
+		}
+		return copied
+	case []string:
+		return append([]string(nil), v...)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lysfighting/ggRMCP/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestBuildTools_InputSchemaOverride(t *testing.T) {
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("overrides.proto"),
+		Package: proto.String("test.overrides"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("UserRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("id", 1)},
+			},
+		},
+	})
+	requestDesc := fd.Messages().ByName("UserRequest")
+
+	overridePath := filepath.Join(t.TempDir(), "overrides.json")
+	require.NoError(t, os.WriteFile(overridePath, []byte(`{
+		"test_overrides_userservice_getuser": {
+			"type": "object",
+			"properties": {"id": {"type": "string", "pattern": "^u-[0-9]+$"}},
+			"required": ["id"],
+			"additionalProperties": false
+		}
+	}`), 0o600))
+
+	overrides, err := LoadInputSchemaOverrides(overridePath)
+	require.NoError(t, err)
+
+	builder := NewMCPToolBuilder(zap.NewNop())
+	builder.SetInputSchemaOverrides(overrides)
+
+	methods := []types.MethodInfo{
+		{
+			Name:             "GetUser",
+			FullName:         "test.overrides.UserService.GetUser",
+			ServiceName:      "test.overrides.UserService",
+			InputDescriptor:  requestDesc,
+			OutputDescriptor: requestDesc,
+		},
+		{
+			Name:             "DeleteUser",
+			FullName:         "test.overrides.UserService.DeleteUser",
+			ServiceName:      "test.overrides.UserService",
+			InputDescriptor:  requestDesc,
+			OutputDescriptor: requestDesc,
+		},
+	}
+
+	tools, err := builder.BuildTools(methods)
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	// The overridden tool uses the configured schema
+	getUser := tools[0].InputSchema.(map[string]interface{})
+	assert.Equal(t, false, getUser["additionalProperties"])
+	idSchema := getUser["properties"].(map[string]interface{})["id"].(map[string]interface{})
+	assert.Equal(t, "^u-[0-9]+$", idSchema["pattern"])
+
+	// Other tools keep the generated schema
+	deleteUser := tools[1].InputSchema.(map[string]interface{})
+	assert.NotContains(t, deleteUser, "additionalProperties")
+	assert.Equal(t, []string{"id"}, deleteUser["x-field-order"])
+
+	// Output schemas are still generated from the real descriptors
+	assert.Equal(t, tools[1].OutputSchema, tools[0].OutputSchema)
+}
+
+func TestLoadInputSchemaOverrides_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadInputSchemaOverrides(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	notObject := filepath.Join(dir, "not_object.json")
+	require.NoError(t, os.WriteFile(notObject, []byte(`{"tool_a": null}`), 0o600))
+	_, err = LoadInputSchemaOverrides(notObject)
+	assert.Error(t, err)
+}
+
+func TestSetInputSchemaOverrides_CopiesAndSkipsGeneration(t *testing.T) {
+	fd := buildTestFile(t, &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("overrides_copy.proto"),
+		Package: proto.String("test.overridecopy"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Request"),
+				Field: []*descriptorpb.FieldDescriptorProto{stringField("id", 1)},
+			},
+		},
+	})
+	requestDesc := fd.Messages().ByName("Request")
+	method := types.MethodInfo{
+		Name:             "Get",
+		FullName:         "test.overridecopy.Service.Get",
+		ServiceName:      "test.overridecopy.Service",
+		InputDescriptor:  requestDesc,
+		OutputDescriptor: requestDesc,
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	builder := NewMCPToolBuilder(zap.New(core))
+
+	overrides := map[string]map[string]interface{}{
+		"test_overridecopy_service_get": {
+			"type":     "object",
+			"required": []interface{}{"id"},
+		},
+	}
+	builder.SetInputSchemaOverrides(overrides)
+
+	// Later changes by the caller do not leak into built tools
+	overrides["test_overridecopy_service_get"]["type"] = "string"
+	overrides["test_overridecopy_service_get"]["required"].([]interface{})[0] = "other"
+
+	tool, err := builder.BuildTool(method)
+	require.NoError(t, err)
+	inputSchema := tool.InputSchema.(map[string]interface{})
+	assert.Equal(t, "object", inputSchema["type"])
+	assert.Equal(t, []interface{}{"id"}, inputSchema["required"])
+
+	// Nor do changes to a built tool's schema
+	inputSchema["type"] = "array"
+	tool, err = builder.BuildTool(method)
+	require.NoError(t, err)
+	assert.Equal(t, "object", tool.InputSchema.(map[string]interface{})["type"])
+
+	// The overridden input schema is never generated
+	assert.Zero(t, logs.FilterMessage("Generating input schema").Len())
+	assert.NotZero(t, logs.FilterMessage("Generating output schema").Len())
+}
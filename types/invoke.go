@@ -0,0 +1,27 @@
+package types
+
+// ResponseFormat selects how aggregated server-streaming responses are encoded
+type ResponseFormat string
+
+const (
+	// ResponseFormatJSON encodes streamed messages as a single JSON array (default)
+	ResponseFormatJSON ResponseFormat = "json"
+
+	// ResponseFormatNDJSON encodes each streamed message as its own line of newline-delimited JSON
+	ResponseFormatNDJSON ResponseFormat = "ndjson"
+)
+
+// IsValid reports whether the response format is supported
+func (f ResponseFormat) IsValid() bool {
+	return f == ResponseFormatJSON || f == ResponseFormatNDJSON
+}
+
+// InvokeOptions holds per-call settings for a single tool invocation
+type InvokeOptions struct {
+	// ResponseFormat selects the encoding of server-streaming results (empty means JSON)
+	ResponseFormat ResponseFormat
+
+	// Compression names the gRPC compressor for this call, overriding the
+	// connection default ("identity" disables compression, empty keeps the default)
+	Compression string
+}
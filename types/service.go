@@ -15,17 +15,19 @@ import (
 type MethodInfo struct {
 	// Method identification
 	Name     string // Method name (e.g., "SayHello")
-	FullName string // Fully qualified method name (e.g., "hello.HelloService.SayHello")
-	ToolName string // Generated tool name for MCP (e.g., "hello_helloservice_sayhello")
+	FullName string // Fully qualified method name (e.g., "com.example.hello.HelloService.SayHello")
+	ToolName string // Generated tool name for MCP (e.g., "com_example_hello_helloservice_sayhello")
 
 	// Service context
-	ServiceName        string // Service name this method belongs to (e.g., "hello.HelloService")
+	ServiceName        string // Service name this method belongs to (e.g., "com.example.hello.HelloService")
 	ServiceDescription string // Service description from proto comments (empty if not available)
+	PackageName        string // Proto package of the service (e.g., "com.example.hello"; empty if the file has no package)
+	FileName           string // Proto file that declares the service (e.g., "hello/hello.proto")
 
 	// Method metadata
 	Description       string                         // Method description from proto comments (empty if not available)
-	InputType         string                         // Protobuf message type name for input (e.g., ".hello.HelloRequest")
-	OutputType        string                         // Protobuf message type name for output (e.g., ".hello.HelloResponse")
+	InputType         string                         // Protobuf message type name for input (e.g., ".com.example.hello.HelloRequest")
+	OutputType        string                         // Protobuf message type name for output (e.g., ".com.example.hello.HelloResponse")
 	InputDescriptor   protoreflect.MessageDescriptor // Protobuf descriptor for input message (used for schema generation)
 	OutputDescriptor  protoreflect.MessageDescriptor // Protobuf descriptor for output message (used for schema generation)
 	IsClientStreaming bool                           // True if method accepts streaming input
@@ -47,7 +49,7 @@ type MethodInfo struct {
 // then appends the lowercase method name.
 //
 // Examples:
-//   - ServiceName: "hello.HelloService", Name: "SayHello" -> "hello_helloservice_sayhello"
+//   - ServiceName: "com.example.hello.HelloService", Name: "SayHello" -> "com_example_hello_helloservice_sayhello"
 //   - ServiceName: "com.example.UserService", Name: "GetUser" -> "com_example_userservice_getuser"
 //   - ServiceName: "SimpleService", Name: "DoThing" -> "simpleservice_dothing"
 func (m *MethodInfo) GenerateToolName() string {